@@ -1,11 +1,64 @@
 package config
 
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
 // ThemesConfig holds configuration for the Themes plugin.
 type ThemesConfig struct {
 	DefaultTheme string `json:"default_theme"`
+	// VSCodeExtensionsDir is the VS Code extensions directory scanned for
+	// installed themes. A leading "~" is the user's home directory.
+	VSCodeExtensionsDir string `json:"vscode_extensions_dir"`
+	// ThemesDir is a directory of theme files loaded on activation and
+	// watched for changes. Empty disables it.
+	ThemesDir string `json:"themes_dir"`
+	// ThemesPollInterval is how often ThemesDir is rescanned, as a
+	// time.ParseDuration string such as "2s"; see PollInterval.
+	ThemesPollInterval string `json:"themes_poll_interval"`
 }
 
+// DefaultVSCodeExtensionsDir is where VS Code installs extensions.
+const DefaultVSCodeExtensionsDir = "~/.vscode/extensions"
+
+// DefaultThemesPollInterval is the default ThemesPollInterval.
+const DefaultThemesPollInterval = "2s"
+
 // DefaultConfig returns the default themes configuration.
 func DefaultConfig() *ThemesConfig {
-	return &ThemesConfig{DefaultTheme: "orchestra-dark"}
+	return &ThemesConfig{
+		DefaultTheme:        "orchestra-dark",
+		VSCodeExtensionsDir: DefaultVSCodeExtensionsDir,
+		ThemesPollInterval:  DefaultThemesPollInterval,
+	}
+}
+
+// PollInterval parses ThemesPollInterval, which must be positive.
+func (c *ThemesConfig) PollInterval() (time.Duration, error) {
+	interval, err := time.ParseDuration(c.ThemesPollInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid themes_poll_interval: %w", err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("invalid themes_poll_interval: %q is not positive", c.ThemesPollInterval)
+	}
+	return interval, nil
+}
+
+// ExpandHome replaces a leading "~" in path with the user's home
+// directory. The path is returned unchanged if the home directory is
+// unknown.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
 }
@@ -12,6 +12,7 @@ type ThemesPlugin struct {
 	ctx    *plugins.PluginContext
 	cfg    *config.ThemesConfig
 	svc    *service.ThemesService
+	dir    *service.ThemeDirWatcher
 }
 
 // NewThemesPlugin creates a new Themes plugin instance.
@@ -26,7 +27,12 @@ func (p *ThemesPlugin) FeatureFlag() string    { return "themes" }
 func (p *ThemesPlugin) ConfigKey() string      { return "themes" }
 
 func (p *ThemesPlugin) DefaultConfig() map[string]any {
-	return map[string]any{"default_theme": "orchestra-dark"}
+	return map[string]any{
+		"default_theme":         "orchestra-dark",
+		"vscode_extensions_dir": config.DefaultVSCodeExtensionsDir,
+		"themes_dir":            "",
+		"themes_poll_interval":  config.DefaultThemesPollInterval,
+	}
 }
 
 // Activate initializes the themes service with built-in themes.
@@ -37,15 +43,39 @@ func (p *ThemesPlugin) Activate(ctx *plugins.PluginContext) error {
 	if dt := ctx.GetConfigString("default_theme"); dt != "" {
 		p.cfg.DefaultTheme = dt
 	}
+	if dir := ctx.GetConfigString("vscode_extensions_dir"); dir != "" {
+		p.cfg.VSCodeExtensionsDir = dir
+	}
+	p.cfg.VSCodeExtensionsDir = config.ExpandHome(p.cfg.VSCodeExtensionsDir)
+	if dir := ctx.GetConfigString("themes_dir"); dir != "" {
+		p.cfg.ThemesDir = config.ExpandHome(dir)
+	}
+	if s := ctx.GetConfigString("themes_poll_interval"); s != "" {
+		p.cfg.ThemesPollInterval = s
+	}
+	interval, err := p.cfg.PollInterval()
+	if err != nil {
+		ctx.Logger.Warn().Err(err).Msg("invalid poll interval, using default")
+		p.cfg.ThemesPollInterval = config.DefaultThemesPollInterval
+		interval, _ = p.cfg.PollInterval()
+	}
 
 	p.svc = service.New(ctx.StoragePath, p.cfg.DefaultTheme, ctx.Logger)
+	if p.cfg.ThemesDir != "" {
+		p.dir = p.svc.WatchThemeDir(p.cfg.ThemesDir, interval)
+	}
 	p.active = true
 	ctx.Logger.Info().Str("plugin", p.ID()).Msg("themes plugin activated")
 	return nil
 }
 
-// Deactivate shuts down the themes plugin.
+// Deactivate shuts down the themes plugin, stopping the themes directory
+// watcher.
 func (p *ThemesPlugin) Deactivate() error {
+	if p.dir != nil {
+		p.dir.Stop()
+		p.dir = nil
+	}
 	p.active = false
 	return nil
 }
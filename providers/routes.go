@@ -1,9 +1,23 @@
 package providers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strings"
+
 	"github.com/gofiber/fiber/v3"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/contrast"
+	"github.com/orchestra-mcp/themes/src/exporter"
+	"github.com/orchestra-mcp/themes/src/generator"
 	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/service"
 	"github.com/orchestra-mcp/themes/src/types"
+	"github.com/orchestra-mcp/themes/src/validator"
 )
 
 // RegisterRoutes registers all REST API routes for the themes plugin.
@@ -12,11 +26,26 @@ func (p *ThemesPlugin) RegisterRoutes(group fiber.Router) {
 
 	themes.Get("/", p.handleListThemes)
 	themes.Get("/active", p.handleGetActive)
+	themes.Get("/keys", p.handleListColorKeys)
 	themes.Put("/active", p.handleSetActive)
+	themes.Get("/stylesheet.css", p.handleStylesheet)
 	themes.Get("/:id", p.handleGetTheme)
+	themes.Put("/:id", p.handleUpdateTheme)
+	themes.Patch("/:id", p.handlePatchTheme)
+	themes.Delete("/:id", p.handleDeleteTheme)
+	themes.Post("/validate", p.handleValidate)
+	themes.Post("/generate", p.handleGenerate)
 	themes.Post("/import", p.handleImport)
 	themes.Post("/import/vscode", p.handleImportVSCode)
+	themes.Post("/import/terminal", p.handleImportTerminal)
+	themes.Post("/import/bundle", p.handleImportBundle)
+	themes.Post("/import/vsix", p.handleImportVSIX)
+	themes.Post("/import/sublime-package", p.handleImportSublimePackage)
+	themes.Post("/discover/vscode", p.handleDiscoverVSCode)
 	themes.Get("/:id/export", p.handleExport)
+	themes.Get("/:id/css", p.handleThemeCSS)
+	themes.Get("/:id/contrast", p.handleContrast)
+	themes.Post("/:id/repair", p.handleRepair)
 }
 
 func (p *ThemesPlugin) handleListThemes(c fiber.Ctx) error {
@@ -35,6 +64,10 @@ func (p *ThemesPlugin) handleGetActive(c fiber.Ctx) error {
 	return c.JSON(theme)
 }
 
+func (p *ThemesPlugin) handleListColorKeys(c fiber.Ctx) error {
+	return c.JSON(fiber.Map{"keys": colorkeys.All()})
+}
+
 type setActiveRequest struct {
 	ID string `json:"id"`
 }
@@ -64,7 +97,11 @@ func (p *ThemesPlugin) handleSetActive(c fiber.Ctx) error {
 
 func (p *ThemesPlugin) handleGetTheme(c fiber.Ctx) error {
 	id := c.Params("id")
-	theme, err := p.svc.GetTheme(id)
+	getTheme := p.svc.GetTheme
+	if c.Query("raw") == "true" {
+		getTheme = p.svc.GetRawTheme
+	}
+	theme, err := getTheme(id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error":   "not_found",
@@ -74,7 +111,22 @@ func (p *ThemesPlugin) handleGetTheme(c fiber.Ctx) error {
 	return c.JSON(theme)
 }
 
-func (p *ThemesPlugin) handleImport(c fiber.Ctx) error {
+func (p *ThemesPlugin) handleUpdateTheme(c fiber.Ctx) error {
+	var theme types.ThemeDef
+	if err := c.Bind().JSON(&theme); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "invalid_request",
+			"message": "Invalid JSON body",
+		})
+	}
+	updated, err := p.svc.UpdateTheme(c.Params("id"), &theme)
+	if err != nil {
+		return themeError(c, err)
+	}
+	return c.JSON(updated)
+}
+
+func (p *ThemesPlugin) handlePatchTheme(c fiber.Ctx) error {
 	body := c.Body()
 	if len(body) == 0 {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -82,13 +134,73 @@ func (p *ThemesPlugin) handleImport(c fiber.Ctx) error {
 			"message": "Request body is empty",
 		})
 	}
-	theme, err := p.svc.ImportTheme(body)
+	patched, err := p.svc.PatchTheme(c.Params("id"), body)
 	if err != nil {
+		return themeError(c, err)
+	}
+	return c.JSON(patched)
+}
+
+func (p *ThemesPlugin) handleDeleteTheme(c fiber.Ctx) error {
+	id := c.Params("id")
+	if err := p.svc.DeleteTheme(id); err != nil {
+		return themeError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (p *ThemesPlugin) handleValidate(c fiber.Ctx) error {
+	body := c.Body()
+	if len(body) == 0 {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "import_error",
+			"error":   "invalid_request",
+			"message": "Request body is empty",
+		})
+	}
+	theme, err := importer.Parse(body)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "invalid_format",
 			"message": err.Error(),
 		})
 	}
+	diags := p.svc.ValidateTheme(theme)
+	return c.JSON(fiber.Map{
+		"valid":       !diags.HasErrors(),
+		"diagnostics": diags,
+	})
+}
+
+func (p *ThemesPlugin) handleGenerate(c fiber.Ctx) error {
+	var seeds generator.Seeds
+	if err := c.Bind().JSON(&seeds); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "invalid_request",
+			"message": "Invalid JSON body",
+		})
+	}
+	theme, err := p.svc.GenerateTheme(seeds)
+	if errors.Is(err, service.ErrThemeExists) {
+		return themeError(c, err)
+	}
+	if err != nil {
+		return importError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(theme)
+}
+
+func (p *ThemesPlugin) handleImport(c fiber.Ctx) error {
+	body := c.Body()
+	if len(body) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "invalid_request",
+			"message": "Request body is empty",
+		})
+	}
+	theme, err := p.svc.ImportTheme(body)
+	if err != nil {
+		return importError(c, err)
+	}
 	return c.Status(fiber.StatusCreated).JSON(theme)
 }
 
@@ -110,10 +222,14 @@ func (p *ThemesPlugin) handleImportVSCode(c fiber.Ctx) error {
 		theme, err = importer.ImportVSCode(body)
 	case importer.FormatTmTheme:
 		theme, err = importer.ImportTmTheme(body)
+	case importer.FormatITermColors:
+		theme, err = importer.ImportITermColors(body)
+	case importer.FormatSublime:
+		theme, err = importer.ImportSublime(body)
 	default:
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error":   "invalid_format",
-			"message": "Expected VS Code JSON theme or .tmTheme XML",
+			"message": "Expected VS Code JSON theme, .tmTheme plist, .sublime-color-scheme or .itermcolors plist",
 		})
 	}
 
@@ -124,19 +240,385 @@ func (p *ThemesPlugin) handleImportVSCode(c fiber.Ctx) error {
 		})
 	}
 
-	p.svc.RegisterTheme(theme)
+	if err := p.svc.RegisterTheme(theme); err != nil {
+		return importError(c, err)
+	}
 	return c.Status(fiber.StatusCreated).JSON(theme)
 }
 
+// terminalFormats are the formats accepted by handleImportTerminal.
+var terminalFormats = map[string]bool{
+	importer.FormatITermColors:     true,
+	importer.FormatAlacritty:       true,
+	importer.FormatKitty:           true,
+	importer.FormatWindowsTerminal: true,
+	importer.FormatXresources:      true,
+}
+
+// handleImportTerminal imports a terminal emulator color scheme. Schemes
+// without a name are named after the "filename" query parameter. Windows
+// Terminal settings with several schemes register each of them and
+// answer like handleImportVSIX.
+func (p *ThemesPlugin) handleImportTerminal(c fiber.Ctx) error {
+	body := c.Body()
+	if len(body) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "invalid_request",
+			"message": "Request body is empty",
+		})
+	}
+	if !terminalFormats[importer.DetectFormat(body)] {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "invalid_format",
+			"message": "Expected iTerm2, Alacritty, kitty, Windows Terminal or Xresources color scheme",
+		})
+	}
+
+	theme, err := importer.ImportFile(c.Query("filename"), body)
+	if errors.Is(err, importer.ErrMultipleSchemes) {
+		result, err := p.svc.ImportWindowsTerminalSchemes(c.Query("filename"), body, service.DiscoverOptions{
+			Overwrite: c.Query("overwrite") == "true",
+		})
+		if err != nil {
+			return importError(c, err)
+		}
+		return c.Status(extensionImportStatus(result)).JSON(result)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "import_error",
+			"message": err.Error(),
+		})
+	}
+	if err := p.svc.RegisterTheme(theme); err != nil {
+		return importError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(theme)
+}
+
+// handleImportBundle imports a VS Code theme whose "include" chain spans
+// several files. The files are sent either as a multipart form, with the
+// theme to import in the "entry" field, or as a zip archive body with the
+// entry in the query.
+func (p *ThemesPlugin) handleImportBundle(c fiber.Ctx) error {
+	var bundle importer.Bundle
+	var entry string
+	var err error
+
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		form, ferr := c.MultipartForm()
+		if ferr != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "invalid_request",
+				"message": ferr.Error(),
+			})
+		}
+		bundle, err = readMultipartBundle(form)
+		entry = c.FormValue("entry")
+	} else {
+		body := c.Body()
+		if len(body) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "invalid_request",
+				"message": "Request body is empty",
+			})
+		}
+		bundle, err = importer.ReadZipBundle(body)
+		entry = c.Query("entry")
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "invalid_format",
+			"message": err.Error(),
+		})
+	}
+
+	theme, err := importer.ImportVSCodeBundle(bundle, entry)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "import_error",
+			"message": err.Error(),
+		})
+	}
+	if err := p.svc.RegisterTheme(theme); err != nil {
+		return importError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(theme)
+}
+
+// handleImportVSIX imports every theme contributed by a .vsix extension
+// package sent as the request body. Themes whose ID is taken are skipped
+// unless the "overwrite" query parameter is true. It answers 201 when at
+// least one theme was registered, 409 when all of them were skipped, and
+// lists the outcome of each theme either way.
+func (p *ThemesPlugin) handleImportVSIX(c fiber.Ctx) error {
+	body := c.Body()
+	if len(body) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "invalid_request",
+			"message": "Request body is empty",
+		})
+	}
+	result, err := p.svc.ImportVSIX(body, service.DiscoverOptions{
+		Overwrite: c.Query("overwrite") == "true",
+	})
+	if err != nil {
+		return importError(c, err)
+	}
+	return c.Status(extensionImportStatus(result)).JSON(result)
+}
+
+// handleImportSublimePackage imports every color scheme of a
+// .sublime-package archive sent as the request body, named by the "name"
+// query parameter. It answers like handleImportVSIX.
+func (p *ThemesPlugin) handleImportSublimePackage(c fiber.Ctx) error {
+	body := c.Body()
+	if len(body) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "invalid_request",
+			"message": "Request body is empty",
+		})
+	}
+	result, err := p.svc.ImportSublimePackage(c.Query("name"), body, service.DiscoverOptions{
+		Overwrite: c.Query("overwrite") == "true",
+	})
+	if err != nil {
+		return importError(c, err)
+	}
+	return c.Status(extensionImportStatus(result)).JSON(result)
+}
+
+// extensionImportStatus is the response status for a package import.
+func extensionImportStatus(result *service.ExtensionImport) int {
+	switch {
+	case result.Imported > 0:
+		return fiber.StatusCreated
+	case result.Skipped == len(result.Themes):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusBadRequest
+	}
+}
+
+// handleDiscoverVSCode scans the configured VS Code extensions directory
+// and registers the themes found. The optional body {"overwrite": true}
+// replaces user themes with the same IDs.
+func (p *ThemesPlugin) handleDiscoverVSCode(c fiber.Ctx) error {
+	var req struct {
+		Overwrite bool `json:"overwrite"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.Bind().JSON(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "invalid_request",
+				"message": "Invalid JSON body",
+			})
+		}
+	}
+	result, err := p.svc.DiscoverVSCodeThemes(p.cfg.VSCodeExtensionsDir, service.DiscoverOptions{
+		Overwrite: req.Overwrite,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "discover_error",
+			"message": err.Error(),
+		})
+	}
+	return c.JSON(result)
+}
+
+// readMultipartBundle collects every file of a multipart form. Multipart
+// filenames lose their directories, so a file sent under a field other
+// than "files" is keyed by the field name, which may hold its relative
+// path; files sent as "files" are keyed by filename.
+func readMultipartBundle(form *multipart.Form) (importer.Bundle, error) {
+	bundle := make(importer.Bundle)
+	for field, headers := range form.File {
+		for _, fh := range headers {
+			name := field
+			if field == "files" {
+				name = fh.Filename
+			}
+			f, err := fh.Open()
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %w", fh.Filename, err)
+			}
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %w", fh.Filename, err)
+			}
+			bundle[importer.CleanPath(name)] = data
+		}
+	}
+	if len(bundle) == 0 {
+		return nil, errors.New("no files uploaded")
+	}
+	return bundle, nil
+}
+
+// importError maps an import or registration failure to a response.
+func importError(c fiber.Ctx, err error) error {
+	status := fiber.StatusBadRequest
+	if errors.Is(err, service.ErrBuiltinTheme) || errors.Is(err, service.ErrDirTheme) {
+		status = fiber.StatusForbidden
+	}
+	body := fiber.Map{
+		"error":   "import_error",
+		"message": err.Error(),
+	}
+	addDiagnostics(body, err)
+	return c.Status(status).JSON(body)
+}
+
+// addDiagnostics attaches validation diagnostics carried by err, if any.
+func addDiagnostics(body fiber.Map, err error) {
+	var verr *validator.Error
+	if errors.As(err, &verr) {
+		body["diagnostics"] = verr.Diagnostics
+	}
+}
+
+// themeError maps a service error on an existing theme to a response.
+func themeError(c fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrThemeNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "not_found",
+			"message": err.Error(),
+		})
+	case errors.Is(err, service.ErrBuiltinTheme), errors.Is(err, service.ErrDirTheme):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   "read_only",
+			"message": err.Error(),
+		})
+	case errors.Is(err, service.ErrThemeExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":   "conflict",
+			"message": err.Error(),
+		})
+	default:
+		body := fiber.Map{
+			"error":   "validation_error",
+			"message": err.Error(),
+		}
+		addDiagnostics(body, err)
+		return c.Status(fiber.StatusBadRequest).JSON(body)
+	}
+}
+
 func (p *ThemesPlugin) handleExport(c fiber.Ctx) error {
 	id := c.Params("id")
-	data, err := p.svc.ExportTheme(id)
+	format := c.Query("format")
+	data, err := p.svc.ExportTheme(id, service.ExportOptions{
+		Raw:    c.Query("raw") == "true",
+		Format: format,
+	})
+	if errors.Is(err, exporter.ErrUnsupportedFormat) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "unsupported_format",
+			"message": err.Error(),
+			"formats": exporter.Formats(),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error":   "not_found",
 			"message": err.Error(),
 		})
 	}
-	c.Set("Content-Type", "application/json")
+	c.Set("Content-Type", exporter.ContentType(format))
 	return c.Send(data)
 }
+
+func (p *ThemesPlugin) handleThemeCSS(c fiber.Ctx) error {
+	scope := c.Query("scope", exporter.ScopeRoot)
+	if scope != exporter.ScopeRoot && scope != exporter.ScopeTheme {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "invalid_request",
+			"message": "scope must be root or theme",
+		})
+	}
+	if !exporter.ValidCSSPrefix(c.Query("prefix")) {
+		return invalidPrefix(c)
+	}
+	data, err := p.svc.ExportTheme(c.Params("id"), service.ExportOptions{
+		Raw:    c.Query("raw") == "true",
+		Format: exporter.FormatCSS,
+		CSS:    exporter.CSSOptions{Scope: scope, Prefix: c.Query("prefix")},
+	})
+	if err != nil {
+		return themeError(c, err)
+	}
+	return sendCached(c, exporter.ContentType(exporter.FormatCSS), data)
+}
+
+func (p *ThemesPlugin) handleStylesheet(c fiber.Ctx) error {
+	if !exporter.ValidCSSPrefix(c.Query("prefix")) {
+		return invalidPrefix(c)
+	}
+	data := p.svc.Stylesheet(c.Query("prefix"))
+	return sendCached(c, exporter.ContentType(exporter.FormatCSS), data)
+}
+
+func invalidPrefix(c fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":   "invalid_request",
+		"message": "prefix may only contain letters, digits, - and _",
+	})
+}
+
+// sendCached sends data with an ETag so clients revalidate cheaply, and
+// answers 304 Not Modified when the client's copy is current. Themes can
+// change at any time, so responses are never served from cache unchecked.
+func sendCached(c fiber.Ctx, contentType string, data []byte) error {
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Set("ETag", etag)
+	c.Set("Cache-Control", "no-cache")
+	if c.Get("If-None-Match") == etag {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set("Content-Type", contentType)
+	return c.Send(data)
+}
+
+func (p *ThemesPlugin) handleContrast(c fiber.Ctx) error {
+	report, err := p.svc.ContrastReport(c.Params("id"), contrast.Options{
+		APCA: c.Query("apca") == "true",
+	})
+	if err != nil {
+		return themeError(c, err)
+	}
+	return c.JSON(report)
+}
+
+type repairRequest struct {
+	Level string `json:"level"`
+	NewID string `json:"new_id"`
+	Name  string `json:"name"`
+}
+
+func (p *ThemesPlugin) handleRepair(c fiber.Ctx) error {
+	var req repairRequest
+	if len(c.Body()) > 0 {
+		if err := c.Bind().JSON(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "invalid_request",
+				"message": "Invalid JSON body",
+			})
+		}
+	}
+	result, err := p.svc.RepairContrast(c.Params("id"), service.RepairOptions{
+		Level: req.Level,
+		NewID: req.NewID,
+		Name:  req.Name,
+	})
+	if err != nil {
+		return themeError(c, err)
+	}
+	if result.Theme != nil {
+		return c.Status(fiber.StatusCreated).JSON(result)
+	}
+	return c.JSON(result)
+}
@@ -1,9 +1,17 @@
 package providers
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/orchestra-mcp/framework/app/plugins"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/contrast"
+	"github.com/orchestra-mcp/themes/src/exporter"
+	"github.com/orchestra-mcp/themes/src/generator"
+	"github.com/orchestra-mcp/themes/src/service"
+	"github.com/orchestra-mcp/themes/src/types"
 )
 
 // McpTools returns MCP tool definitions contributed by the Themes plugin.
@@ -32,6 +40,163 @@ func (p *ThemesPlugin) McpTools() []plugins.McpToolDefinition {
 			},
 			Handler: p.toolSetActiveTheme,
 		},
+		{
+			Name:        "list_color_keys",
+			Description: "List the canonical theme color keys with descriptions, defaults and aliases",
+			InputSchema: map[string]any{},
+			Handler:     p.toolListColorKeys,
+		},
+		{
+			Name:        "validate_theme",
+			Description: "Validate a theme definition and return diagnostics with severity and JSON path",
+			InputSchema: map[string]any{
+				"theme": map[string]any{
+					"type":        "object",
+					"description": "Theme definition to validate",
+				},
+			},
+			Handler: p.toolValidateTheme,
+		},
+		{
+			Name:        "check_contrast",
+			Description: "Audit a theme's foreground/background and token colors for WCAG AA/AAA contrast",
+			InputSchema: map[string]any{
+				"id": map[string]any{
+					"type":        "string",
+					"description": "Theme ID to audit; defaults to the active theme",
+				},
+				"apca": map[string]any{
+					"type":        "boolean",
+					"description": "Also report APCA lightness contrast (Lc)",
+				},
+			},
+			Handler: p.toolCheckContrast,
+		},
+		{
+			Name:        "repair_contrast",
+			Description: "Fix a theme's failing contrast by adjusting foreground lightness; returns a merge patch or registers a derived theme",
+			InputSchema: map[string]any{
+				"id": map[string]any{
+					"type":        "string",
+					"description": "Theme ID to repair; defaults to the active theme",
+				},
+				"level": map[string]any{
+					"type":        "string",
+					"description": "Target level: AA (default) or AAA",
+				},
+				"new_id": map[string]any{
+					"type":        "string",
+					"description": "Register the result as a new theme with this unused ID extending the source; the source ID repairs it in place",
+				},
+				"name": map[string]any{
+					"type":        "string",
+					"description": "Display name of the new theme",
+				},
+			},
+			Handler: p.toolRepairContrast,
+		},
+		{
+			Name:        "generate_theme",
+			Description: "Create and register a complete theme (all color keys and token colors) from background, foreground and accent seed colors; fails if a theme with the ID already exists",
+			InputSchema: map[string]any{
+				"id": map[string]any{
+					"type":        "string",
+					"description": "ID of the new theme",
+				},
+				"name": map[string]any{
+					"type":        "string",
+					"description": "Display name; defaults to the ID",
+				},
+				"type": map[string]any{
+					"type":        "string",
+					"description": "light or dark; inferred from the background when omitted",
+				},
+				"background": map[string]any{
+					"type":        "string",
+					"description": "Background color (hex, rgb(), hsl(), oklch() or CSS name)",
+				},
+				"foreground": map[string]any{
+					"type":        "string",
+					"description": "Text color",
+				},
+				"accent": map[string]any{
+					"type":        "string",
+					"description": "Accent color used for links, buttons, the status bar and token hues",
+				},
+			},
+			Handler: p.toolGenerateTheme,
+		},
+		{
+			Name:        "export_theme",
+			Description: "Export a theme as Orchestra JSON, a VS Code or TextMate theme, CSS, or a terminal color scheme (Alacritty, kitty, Windows Terminal, iTerm2, Xresources)",
+			InputSchema: map[string]any{
+				"id": map[string]any{
+					"type":        "string",
+					"description": "Theme ID; defaults to the active theme",
+				},
+				"format": map[string]any{
+					"type":        "string",
+					"description": "One of " + strings.Join(exporter.Formats(), ", ") + "; defaults to orchestra",
+				},
+				"raw": map[string]any{
+					"type":        "boolean",
+					"description": "Export the stored definition without resolving extends (ignored by terminal formats)",
+				},
+			},
+			Handler: p.toolExportTheme,
+		},
+		{
+			Name:        "discover_vscode_themes",
+			Description: "Scan the configured VS Code extensions directory and import the installed themes, reporting which were added, skipped or failed",
+			InputSchema: map[string]any{
+				"overwrite": map[string]any{
+					"type":        "boolean",
+					"description": "Replace user themes that have the same ID as a discovered theme",
+				},
+			},
+			Handler: p.toolDiscoverVSCodeThemes,
+		},
+		{
+			Name:        "update_theme",
+			Description: "Replace a user theme definition",
+			InputSchema: map[string]any{
+				"id": map[string]any{
+					"type":        "string",
+					"description": "Theme ID to update",
+				},
+				"theme": map[string]any{
+					"type":        "object",
+					"description": "Complete theme definition",
+				},
+			},
+			Handler: p.toolUpdateTheme,
+		},
+		{
+			Name:        "patch_theme",
+			Description: "Apply a JSON merge patch to a user theme's colors or token colors",
+			InputSchema: map[string]any{
+				"id": map[string]any{
+					"type":        "string",
+					"description": "Theme ID to patch",
+				},
+				"patch": map[string]any{
+					"type":        "object",
+					"description": "JSON merge patch; null values remove keys",
+				},
+			},
+			Handler: p.toolPatchTheme,
+		},
+		{
+			Name:        "delete_theme",
+			Description: "Delete a user theme by ID",
+			InputSchema: map[string]any{
+				"id": map[string]any{
+					"type":        "string",
+					"description": "Theme ID to delete",
+				},
+			},
+			Handler: p.toolDeleteTheme,
+		},
 	}
 }
 
@@ -58,3 +223,158 @@ func (p *ThemesPlugin) toolSetActiveTheme(input map[string]any) (any, error) {
 	}
 	return map[string]any{"active_theme": id}, nil
 }
+
+func (p *ThemesPlugin) toolListColorKeys(_ map[string]any) (any, error) {
+	return map[string]any{"keys": colorkeys.All()}, nil
+}
+
+func (p *ThemesPlugin) toolValidateTheme(input map[string]any) (any, error) {
+	theme, err := themeFromInput(input, "theme")
+	if err != nil {
+		return nil, err
+	}
+	diags := p.svc.ValidateTheme(theme)
+	return map[string]any{
+		"valid":       !diags.HasErrors(),
+		"diagnostics": diags,
+	}, nil
+}
+
+func (p *ThemesPlugin) toolCheckContrast(input map[string]any) (any, error) {
+	id, _ := input["id"].(string)
+	if id == "" {
+		active := p.svc.GetActiveTheme()
+		if active == nil {
+			return nil, fmt.Errorf("no active theme")
+		}
+		id = active.ID
+	}
+	apca, _ := input["apca"].(bool)
+	return p.svc.ContrastReport(id, contrast.Options{APCA: apca})
+}
+
+func (p *ThemesPlugin) toolRepairContrast(input map[string]any) (any, error) {
+	id, _ := input["id"].(string)
+	if id == "" {
+		active := p.svc.GetActiveTheme()
+		if active == nil {
+			return nil, fmt.Errorf("no active theme")
+		}
+		id = active.ID
+	}
+	level, _ := input["level"].(string)
+	newID, _ := input["new_id"].(string)
+	name, _ := input["name"].(string)
+	return p.svc.RepairContrast(id, service.RepairOptions{
+		Level: level,
+		NewID: newID,
+		Name:  name,
+	})
+}
+
+func (p *ThemesPlugin) toolGenerateTheme(input map[string]any) (any, error) {
+	seeds := generator.Seeds{}
+	seeds.ID, _ = input["id"].(string)
+	if seeds.ID == "" {
+		return nil, fmt.Errorf("theme id is required")
+	}
+	seeds.Name, _ = input["name"].(string)
+	seeds.Type, _ = input["type"].(string)
+	seeds.Background, _ = input["background"].(string)
+	seeds.Foreground, _ = input["foreground"].(string)
+	seeds.Accent, _ = input["accent"].(string)
+	return p.svc.GenerateTheme(seeds)
+}
+
+func (p *ThemesPlugin) toolExportTheme(input map[string]any) (any, error) {
+	id, _ := input["id"].(string)
+	if id == "" {
+		active := p.svc.GetActiveTheme()
+		if active == nil {
+			return nil, fmt.Errorf("no active theme")
+		}
+		id = active.ID
+	}
+	format, _ := input["format"].(string)
+	if format == "" {
+		format = exporter.FormatOrchestra
+	}
+	raw, _ := input["raw"].(bool)
+	data, err := p.svc.ExportTheme(id, service.ExportOptions{Raw: raw, Format: format})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"id":           id,
+		"format":       format,
+		"content_type": exporter.ContentType(format),
+		"content":      string(data),
+	}, nil
+}
+
+func (p *ThemesPlugin) toolDiscoverVSCodeThemes(input map[string]any) (any, error) {
+	overwrite, _ := input["overwrite"].(bool)
+	return p.svc.DiscoverVSCodeThemes(p.cfg.VSCodeExtensionsDir, service.DiscoverOptions{
+		Overwrite: overwrite,
+	})
+}
+
+func (p *ThemesPlugin) toolUpdateTheme(input map[string]any) (any, error) {
+	id, _ := input["id"].(string)
+	if id == "" {
+		return nil, fmt.Errorf("theme id is required")
+	}
+	theme, err := themeFromInput(input, "theme")
+	if err != nil {
+		return nil, err
+	}
+	return p.svc.UpdateTheme(id, theme)
+}
+
+func (p *ThemesPlugin) toolPatchTheme(input map[string]any) (any, error) {
+	id, _ := input["id"].(string)
+	if id == "" {
+		return nil, fmt.Errorf("theme id is required")
+	}
+	patch, ok := input["patch"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("patch object is required")
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+	return p.svc.PatchTheme(id, data)
+}
+
+func (p *ThemesPlugin) toolDeleteTheme(input map[string]any) (any, error) {
+	id, _ := input["id"].(string)
+	if id == "" {
+		return nil, fmt.Errorf("theme id is required")
+	}
+	if err := p.svc.DeleteTheme(id); err != nil {
+		return nil, err
+	}
+	result := map[string]any{"deleted": id}
+	if active := p.svc.GetActiveTheme(); active != nil {
+		result["active_theme"] = active.ID
+	}
+	return result, nil
+}
+
+// themeFromInput decodes the theme object passed under key.
+func themeFromInput(input map[string]any, key string) (*types.ThemeDef, error) {
+	raw, ok := input[key].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s object is required", key)
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	var theme types.ThemeDef
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return &theme, nil
+}
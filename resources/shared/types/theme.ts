@@ -10,6 +10,49 @@ export interface TokenColor {
   settings: Record<string, string>;
 }
 
+/** Style applied to semantic tokens matched by a selector. */
+export interface SemanticTokenStyle {
+  foreground?: string;
+  font_style?: string;
+  bold?: boolean;
+  italic?: boolean;
+  underline?: boolean;
+  strikethrough?: boolean;
+}
+
+/** Semantic token rule; selector is "type.modifier:language". */
+export interface SemanticTokenColor {
+  selector: string;
+  token_type: string;
+  modifiers?: string[];
+  language?: string;
+  style: SemanticTokenStyle;
+}
+
+/** Integrated terminal palette: 16 ANSI colors plus special colors. */
+export interface TerminalPalette {
+  black: string;
+  red: string;
+  green: string;
+  yellow: string;
+  blue: string;
+  magenta: string;
+  cyan: string;
+  white: string;
+  bright_black: string;
+  bright_red: string;
+  bright_green: string;
+  bright_yellow: string;
+  bright_blue: string;
+  bright_magenta: string;
+  bright_cyan: string;
+  bright_white: string;
+  background: string;
+  foreground: string;
+  cursor: string;
+  selection: string;
+}
+
 /** Complete theme definition returned by the themes API. */
 export interface ThemeDef {
   id: string;
@@ -18,8 +61,18 @@ export interface ThemeDef {
   author: string;
   type: 'light' | 'dark';
   source?: string;
+  extends?: string;
   colors: Record<string, string>;
   token_colors?: TokenColor[];
+  semantic_highlighting?: boolean;
+  semantic_token_colors?: SemanticTokenColor[];
+  /** Always complete in API responses; partial in raw definitions. */
+  terminal?: Partial<TerminalPalette>;
+  /**
+   * Color keys the server filled in because the theme did not define them;
+   * terminal palette fields appear as "terminal.<field>".
+   */
+  synthesized?: string[];
 }
 
 /** Event emitted when the active theme changes. */
@@ -1,6 +1,9 @@
 package builtin
 
-import "github.com/orchestra-mcp/themes/src/types"
+import (
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/types"
+)
 
 // LightTheme returns the built-in orchestra-light theme.
 func LightTheme() *types.ThemeDef {
@@ -11,6 +14,7 @@ func LightTheme() *types.ThemeDef {
 		Author:      "Orchestra Team",
 		Type:        "light",
 		Colors:      lightColors(),
+		Terminal:    lightTerminal(),
 	}
 }
 
@@ -23,62 +27,69 @@ func DarkTheme() *types.ThemeDef {
 		Author:      "Orchestra Team",
 		Type:        "dark",
 		Colors:      darkColors(),
+		Terminal:    darkTerminal(),
 	}
 }
 
+// lightColors returns the light palette from the color key registry.
 func lightColors() map[string]string {
-	return map[string]string{
-		"background":           "#FFFFFF",
-		"foreground":           "#1E1E1E",
-		"primary":              "#2563EB",
-		"secondary":            "#64748B",
-		"accent":               "#8B5CF6",
-		"border":               "#E2E8F0",
-		"sidebar.background":   "#F8FAFC",
-		"sidebar.foreground":   "#334155",
-		"editor.background":    "#FFFFFF",
-		"editor.foreground":    "#1E1E1E",
-		"titlebar.background":  "#F1F5F9",
-		"titlebar.foreground":  "#0F172A",
-		"statusbar.background": "#2563EB",
-		"statusbar.foreground": "#FFFFFF",
-		"input.background":     "#FFFFFF",
-		"input.foreground":     "#1E1E1E",
-		"input.border":         "#CBD5E1",
-		"button.background":    "#2563EB",
-		"button.foreground":    "#FFFFFF",
-		"error":                "#DC2626",
-		"warning":              "#D97706",
-		"success":              "#16A34A",
-		"info":                 "#2563EB",
-	}
+	return colorkeys.Defaults("light")
 }
 
+// darkColors returns the dark palette from the color key registry.
 func darkColors() map[string]string {
-	return map[string]string{
-		"background":           "#1E1E2E",
-		"foreground":           "#CDD6F4",
-		"primary":              "#89B4FA",
-		"secondary":            "#A6ADC8",
-		"accent":               "#CBA6F7",
-		"border":               "#313244",
-		"sidebar.background":   "#181825",
-		"sidebar.foreground":   "#BAC2DE",
-		"editor.background":    "#1E1E2E",
-		"editor.foreground":    "#CDD6F4",
-		"titlebar.background":  "#11111B",
-		"titlebar.foreground":  "#CDD6F4",
-		"statusbar.background": "#89B4FA",
-		"statusbar.foreground": "#1E1E2E",
-		"input.background":     "#313244",
-		"input.foreground":     "#CDD6F4",
-		"input.border":         "#45475A",
-		"button.background":    "#89B4FA",
-		"button.foreground":    "#1E1E2E",
-		"error":                "#F38BA8",
-		"warning":              "#FAB387",
-		"success":              "#A6E3A1",
-		"info":                 "#89B4FA",
+	return colorkeys.Defaults("dark")
+}
+
+// lightTerminal returns the light terminal palette.
+func lightTerminal() *types.TerminalPalette {
+	return &types.TerminalPalette{
+		Black:         "#334155",
+		Red:           "#DC2626",
+		Green:         "#16A34A",
+		Yellow:        "#CA8A04",
+		Blue:          "#2563EB",
+		Magenta:       "#9333EA",
+		Cyan:          "#0891B2",
+		White:         "#CBD5E1",
+		BrightBlack:   "#64748B",
+		BrightRed:     "#EF4444",
+		BrightGreen:   "#22C55E",
+		BrightYellow:  "#EAB308",
+		BrightBlue:    "#3B82F6",
+		BrightMagenta: "#A855F7",
+		BrightCyan:    "#06B6D4",
+		BrightWhite:   "#F1F5F9",
+		Background:    "#FFFFFF",
+		Foreground:    "#1E1E1E",
+		Cursor:        "#1E1E1E",
+		Selection:     "#BFDBFE",
+	}
+}
+
+// darkTerminal returns the dark terminal palette.
+func darkTerminal() *types.TerminalPalette {
+	return &types.TerminalPalette{
+		Black:         "#45475A",
+		Red:           "#F38BA8",
+		Green:         "#A6E3A1",
+		Yellow:        "#F9E2AF",
+		Blue:          "#89B4FA",
+		Magenta:       "#F5C2E7",
+		Cyan:          "#94E2D5",
+		White:         "#BAC2DE",
+		BrightBlack:   "#585B70",
+		BrightRed:     "#F5A3BA",
+		BrightGreen:   "#BDEBB9",
+		BrightYellow:  "#FBEAC4",
+		BrightBlue:    "#A6C7FB",
+		BrightMagenta: "#F8D4ED",
+		BrightCyan:    "#B0EADF",
+		BrightWhite:   "#A6ADC8",
+		Background:    "#1E1E2E",
+		Foreground:    "#CDD6F4",
+		Cursor:        "#F5E0DC",
+		Selection:     "#45475A",
 	}
 }
 
@@ -0,0 +1,123 @@
+// Package color parses CSS-style color values, normalizes them to a
+// canonical hex form and derives new colors from them.
+package color
+
+import (
+	"fmt"
+	"math"
+)
+
+// Color is an sRGB color with straight alpha. Channels are in [0, 1].
+type Color struct {
+	R, G, B, A float64
+}
+
+// Hex formats c as #RRGGBB, or #RRGGBBAA when it is not fully opaque.
+func (c Color) Hex() string {
+	r, g, b := to8(c.R), to8(c.G), to8(c.B)
+	if a := to8(c.A); a < 255 {
+		return fmt.Sprintf("#%02X%02X%02X%02X", r, g, b, a)
+	}
+	return fmt.Sprintf("#%02X%02X%02X", r, g, b)
+}
+
+func to8(v float64) int {
+	return int(math.Round(clamp01(v) * 255))
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// HSL returns hue in degrees [0, 360) and saturation and lightness in
+// [0, 1].
+func (c Color) HSL() (h, s, l float64) {
+	maxC := math.Max(c.R, math.Max(c.G, c.B))
+	minC := math.Min(c.R, math.Min(c.G, c.B))
+	l = (maxC + minC) / 2
+	d := maxC - minC
+	if d == 0 {
+		return 0, 0, l
+	}
+
+	s = d / (1 - math.Abs(2*l-1))
+	switch maxC {
+	case c.R:
+		h = math.Mod((c.G-c.B)/d, 6)
+	case c.G:
+		h = (c.B-c.R)/d + 2
+	default:
+		h = (c.R-c.G)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l
+}
+
+// FromHSL builds a color from hue in degrees and saturation, lightness
+// and alpha in [0, 1].
+func FromHSL(h, s, l, a float64) Color {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	s, l = clamp01(s), clamp01(l)
+
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - chroma/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return Color{R: r + m, G: g + m, B: b + m, A: clamp01(a)}
+}
+
+// Lighten shifts HSL lightness by amount, which may be negative.
+func (c Color) Lighten(amount float64) Color {
+	h, s, l := c.HSL()
+	return FromHSL(h, s, l+amount, c.A)
+}
+
+// Mix blends a towards b; t=0 yields a and t=1 yields b.
+func Mix(a, b Color, t float64) Color {
+	t = clamp01(t)
+	return Color{
+		R: a.R + (b.R-a.R)*t,
+		G: a.G + (b.G-a.G)*t,
+		B: a.B + (b.B-a.B)*t,
+		A: a.A + (b.A-a.A)*t,
+	}
+}
+
+// Luminance returns the WCAG relative luminance of c, ignoring alpha.
+func (c Color) Luminance() float64 {
+	return 0.2126*linear(c.R) + 0.7152*linear(c.G) + 0.0722*linear(c.B)
+}
+
+func linear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// IsLight reports whether c reads as a light color, i.e. dark text is
+// more legible on it than light text.
+func (c Color) IsLight() bool {
+	return c.Luminance() > 0.179
+}
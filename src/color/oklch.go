@@ -0,0 +1,76 @@
+package color
+
+import "math"
+
+// OKLCH returns the color in the OKLCH space: perceptual lightness in
+// [0, 1], chroma (roughly [0, 0.4]) and hue in degrees [0, 360).
+func (c Color) OKLCH() (l, chroma, h float64) {
+	r, g, b := linear(c.R), linear(c.G), linear(c.B)
+
+	lc := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	mc := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	sc := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	l = 0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc
+	a := 1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc
+	bb := 0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc
+
+	chroma = math.Hypot(a, bb)
+	h = math.Atan2(bb, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return l, chroma, h
+}
+
+// FromOKLCH builds a color from OKLCH coordinates. Colors outside the
+// sRGB gamut have their chroma reduced, keeping lightness and hue, until
+// they fit.
+func FromOKLCH(l, chroma, h, a float64) Color {
+	l = clamp01(l)
+	chroma = math.Max(0, chroma)
+
+	r, g, b, ok := oklchToLinear(l, chroma, h)
+	if !ok {
+		lo, hi := 0.0, chroma
+		for i := 0; i < 24; i++ {
+			mid := (lo + hi) / 2
+			if _, _, _, inGamut := oklchToLinear(l, mid, h); inGamut {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		r, g, b, _ = oklchToLinear(l, lo, h)
+	}
+	return Color{R: encode(r), G: encode(g), B: encode(b), A: clamp01(a)}
+}
+
+// oklchToLinear converts OKLCH to linear sRGB and reports whether the
+// result lies inside the sRGB gamut.
+func oklchToLinear(l, chroma, h float64) (r, g, b float64, ok bool) {
+	rad := h * math.Pi / 180
+	a, bb := chroma*math.Cos(rad), chroma*math.Sin(rad)
+
+	lc := l + 0.3963377774*a + 0.2158037573*bb
+	mc := l - 0.1055613458*a - 0.0638541728*bb
+	sc := l - 0.0894841775*a - 1.2914855480*bb
+	lc, mc, sc = lc*lc*lc, mc*mc*mc, sc*sc*sc
+
+	r = 4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc
+	g = -1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc
+	b = -0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc
+
+	const eps = 1e-6
+	ok = r >= -eps && r <= 1+eps && g >= -eps && g <= 1+eps && b >= -eps && b <= 1+eps
+	return r, g, b, ok
+}
+
+// encode applies the sRGB transfer function to a linear channel value.
+func encode(v float64) float64 {
+	v = clamp01(v)
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
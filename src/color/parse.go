@@ -0,0 +1,282 @@
+package color
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Parse parses a CSS color value: hex (#RGB, #RGBA, #RRGGBB, #RRGGBBAA),
+// rgb()/rgba(), hsl()/hsla(), oklch(), a CSS named color or
+// "transparent". Both the legacy comma syntax and the modern space
+// syntax with "/ alpha" are accepted.
+func Parse(s string) (Color, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	if v == "" {
+		return Color{}, fmt.Errorf("empty color")
+	}
+
+	if strings.HasPrefix(v, "#") {
+		c, ok := parseHex(v[1:])
+		if !ok {
+			return Color{}, fmt.Errorf("invalid hex color %q", s)
+		}
+		return c, nil
+	}
+	if v == "transparent" {
+		return Color{}, nil
+	}
+	if hex, ok := namedColors[v]; ok {
+		c, _ := parseHex(hex[1:])
+		return c, nil
+	}
+
+	open := strings.IndexByte(v, '(')
+	if open <= 0 || !strings.HasSuffix(v, ")") {
+		return Color{}, fmt.Errorf("unrecognized color %q", s)
+	}
+	name := strings.TrimSpace(v[:open])
+	args, alpha, err := splitArgs(v[open+1 : len(v)-1])
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+
+	var c Color
+	switch name {
+	case "rgb", "rgba":
+		c, err = parseRGB(args)
+	case "hsl", "hsla":
+		c, err = parseHSL(args)
+	case "oklch":
+		c, err = parseOKLCH(args)
+	default:
+		return Color{}, fmt.Errorf("unsupported color function %q", name)
+	}
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+
+	c.A = 1
+	if alpha != "" {
+		a, err := parseAlpha(alpha)
+		if err != nil {
+			return Color{}, fmt.Errorf("invalid color %q: %w", s, err)
+		}
+		c.A = a
+	}
+	return c, nil
+}
+
+// Normalize parses s and returns it in canonical form: uppercase
+// #RRGGBB, or #RRGGBBAA when the color is not fully opaque.
+func Normalize(s string) (string, error) {
+	c, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return c.Hex(), nil
+}
+
+// Valid reports whether s is a color Parse accepts.
+func Valid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// MustParse is like Parse but panics on error. It is meant for color
+// literals known to be valid.
+func MustParse(s string) Color {
+	c, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func parseHex(hex string) (Color, bool) {
+	switch len(hex) {
+	case 3, 4:
+		expanded := make([]byte, 0, len(hex)*2)
+		for i := 0; i < len(hex); i++ {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	case 6, 8:
+	default:
+		return Color{}, false
+	}
+
+	ch := [4]float64{1, 1, 1, 1}
+	for i := 0; i < len(hex)/2; i++ {
+		hi, ok1 := hexDigit(hex[i*2])
+		lo, ok2 := hexDigit(hex[i*2+1])
+		if !ok1 || !ok2 {
+			return Color{}, false
+		}
+		ch[i] = float64(hi<<4|lo) / 255
+	}
+	return Color{R: ch[0], G: ch[1], B: ch[2], A: ch[3]}, true
+}
+
+func hexDigit(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10, true
+	case c >= 'A' && c <= 'F':
+		return int(c-'A') + 10, true
+	default:
+		return 0, false
+	}
+}
+
+// splitArgs splits a color function body into its three channel
+// arguments and an optional alpha argument.
+func splitArgs(body string) (args []string, alpha string, err error) {
+	if slash := strings.IndexByte(body, '/'); slash >= 0 {
+		alpha = strings.TrimSpace(body[slash+1:])
+		body = body[:slash]
+		if alpha == "" {
+			return nil, "", fmt.Errorf("missing alpha after '/'")
+		}
+	}
+
+	if strings.Contains(body, ",") {
+		for _, part := range strings.Split(body, ",") {
+			args = append(args, strings.TrimSpace(part))
+		}
+	} else {
+		args = strings.Fields(body)
+	}
+
+	if len(args) == 4 && alpha == "" {
+		args, alpha = args[:3], args[3]
+	}
+	if len(args) != 3 {
+		return nil, "", fmt.Errorf("expected 3 channels, got %d", len(args))
+	}
+	for _, a := range args {
+		if a == "" {
+			return nil, "", fmt.Errorf("empty channel")
+		}
+	}
+	return args, alpha, nil
+}
+
+// parseNumber parses a plain number or a percentage. Percentages are
+// returned as a fraction with pct set.
+func parseNumber(s string) (v float64, pct bool, err error) {
+	if s == "none" {
+		return 0, false, nil
+	}
+	if strings.HasSuffix(s, "%") {
+		v, err = ParseFinite(strings.TrimSuffix(s, "%"))
+		return v / 100, true, err
+	}
+	v, err = ParseFinite(s)
+	return v, false, err
+}
+
+// ParseFinite parses a float like strconv.ParseFloat but rejects the NaN
+// and infinity spellings strconv accepts.
+func ParseFinite(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err == nil && (math.IsNaN(v) || math.IsInf(v, 0)) {
+		return 0, fmt.Errorf("non-finite number %q", s)
+	}
+	return v, err
+}
+
+func parseAlpha(s string) (float64, error) {
+	v, _, err := parseNumber(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid alpha %q", s)
+	}
+	return clamp01(v), nil
+}
+
+// parseHue parses an angle in degrees; deg, rad, grad and turn units are
+// accepted.
+func parseHue(s string) (float64, error) {
+	units := []struct {
+		suffix string
+		scale  float64
+	}{
+		{"deg", 1},
+		{"grad", 0.9},
+		{"rad", 180 / math.Pi},
+		{"turn", 360},
+	}
+	scale := 1.0
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s, scale = strings.TrimSuffix(s, u.suffix), u.scale
+			break
+		}
+	}
+	if s == "none" {
+		return 0, nil
+	}
+	v, err := ParseFinite(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hue %q", s)
+	}
+	return v * scale, nil
+}
+
+func parseRGB(args []string) (Color, error) {
+	var ch [3]float64
+	for i, a := range args {
+		v, pct, err := parseNumber(a)
+		if err != nil {
+			return Color{}, fmt.Errorf("invalid channel %q", a)
+		}
+		if !pct {
+			v /= 255
+		}
+		ch[i] = clamp01(v)
+	}
+	return Color{R: ch[0], G: ch[1], B: ch[2]}, nil
+}
+
+func parseHSL(args []string) (Color, error) {
+	h, err := parseHue(args[0])
+	if err != nil {
+		return Color{}, err
+	}
+	var sl [2]float64
+	for i, a := range args[1:] {
+		v, pct, err := parseNumber(a)
+		if err != nil {
+			return Color{}, fmt.Errorf("invalid channel %q", a)
+		}
+		if !pct {
+			// Modern syntax allows bare numbers meaning percentages.
+			v /= 100
+		}
+		sl[i] = v
+	}
+	return FromHSL(h, sl[0], sl[1], 1), nil
+}
+
+func parseOKLCH(args []string) (Color, error) {
+	l, _, err := parseNumber(args[0])
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid lightness %q", args[0])
+	}
+	c, pct, err := parseNumber(args[1])
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid chroma %q", args[1])
+	}
+	if pct {
+		// CSS Color 4: 100% chroma is 0.4.
+		c *= 0.4
+	}
+	h, err := parseHue(args[2])
+	if err != nil {
+		return Color{}, err
+	}
+	return FromOKLCH(l, c, h, 1), nil
+}
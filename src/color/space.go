@@ -0,0 +1,29 @@
+package color
+
+import "math"
+
+// FromLinearSRGB returns the color with the given linear-light sRGB
+// channels. Channels outside [0, 1] are clipped.
+func FromLinearSRGB(r, g, b, a float64) Color {
+	return Color{R: encode(r), G: encode(g), B: encode(b), A: clamp01(a)}
+}
+
+// FromDisplayP3 converts gamma-encoded Display P3 channels to sRGB.
+// Colors outside the sRGB gamut are clipped.
+func FromDisplayP3(r, g, b, a float64) Color {
+	r, g, b = linear(r), linear(g), linear(b)
+	return FromLinearSRGB(
+		1.2249401*r-0.2249404*g,
+		-0.0420569*r+1.0420571*g,
+		-0.0196376*r-0.0786361*g+1.0982735*b,
+		a,
+	)
+}
+
+// FromGamma converts channels encoded with a pure power-law gamma, such
+// as the 1.8 gamma of Apple's generic RGB space, to sRGB. The primaries
+// are taken to be those of sRGB.
+func FromGamma(r, g, b, a, gamma float64) Color {
+	dec := func(v float64) float64 { return math.Pow(clamp01(v), gamma) }
+	return FromLinearSRGB(dec(r), dec(g), dec(b), a)
+}
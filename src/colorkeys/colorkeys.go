@@ -0,0 +1,201 @@
+// Package colorkeys is the registry of canonical semantic color keys.
+//
+// Every theme color map uses these names: the built-in themes take their
+// palettes from the registry defaults, and every importer maps its source
+// format onto them. Keys that do not correspond to a registry entry are
+// kept under a "raw." prefix by the importers.
+package colorkeys
+
+import (
+	"sort"
+	"strings"
+)
+
+// Key describes one canonical color key.
+type Key struct {
+	Name        string   `json:"name"`
+	Group       string   `json:"group"`
+	Description string   `json:"description"`
+	Light       string   `json:"light"`
+	Dark        string   `json:"dark"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+// Key groups, in display order.
+const (
+	GroupBase      = "base"
+	GroupWorkbench = "workbench"
+	GroupEditor    = "editor"
+	GroupControls  = "controls"
+	GroupStatus    = "status"
+)
+
+// Canonical key names.
+const (
+	Background            = "background"
+	Foreground            = "foreground"
+	Primary               = "primary"
+	Secondary             = "secondary"
+	Accent                = "accent"
+	Border                = "border"
+	FocusBorder           = "focus.border"
+	SidebarBackground     = "sidebar.background"
+	SidebarForeground     = "sidebar.foreground"
+	ActivityBarBackground = "activitybar.background"
+	ActivityBarForeground = "activitybar.foreground"
+	TitleBarBackground    = "titlebar.background"
+	TitleBarForeground    = "titlebar.foreground"
+	StatusBarBackground   = "statusbar.background"
+	StatusBarForeground   = "statusbar.foreground"
+	EditorBackground      = "editor.background"
+	EditorForeground      = "editor.foreground"
+	EditorCursor          = "editor.cursor"
+	EditorSelection       = "editor.selection"
+	EditorLineHighlight   = "editor.lineHighlight"
+	ListSelection         = "list.selection"
+	InputBackground       = "input.background"
+	InputForeground       = "input.foreground"
+	InputBorder           = "input.border"
+	ButtonBackground      = "button.background"
+	ButtonForeground      = "button.foreground"
+	Error                 = "error"
+	Warning               = "warning"
+	Success               = "success"
+	Info                  = "info"
+)
+
+// RawPrefix marks source-format keys preserved verbatim by importers.
+const RawPrefix = "raw."
+
+var registry = []Key{
+	{Name: Background, Group: GroupBase, Description: "Application background", Light: "#FFFFFF", Dark: "#1E1E2E", Aliases: []string{"bg-primary"}},
+	{Name: Foreground, Group: GroupBase, Description: "Default text color", Light: "#1E1E1E", Dark: "#CDD6F4", Aliases: []string{"text-primary"}},
+	{Name: Primary, Group: GroupBase, Description: "Primary brand color for emphasis and links", Light: "#2563EB", Dark: "#89B4FA"},
+	{Name: Secondary, Group: GroupBase, Description: "Muted color for secondary text and icons", Light: "#64748B", Dark: "#A6ADC8"},
+	{Name: Accent, Group: GroupBase, Description: "Accent color for highlights and badges", Light: "#8B5CF6", Dark: "#CBA6F7"},
+	{Name: Border, Group: GroupBase, Description: "Default border and divider color", Light: "#E2E8F0", Dark: "#313244"},
+	{Name: FocusBorder, Group: GroupBase, Description: "Outline of focused elements", Light: "#2563EB", Dark: "#89B4FA", Aliases: []string{"border-focus"}},
+
+	{Name: SidebarBackground, Group: GroupWorkbench, Description: "Side bar background", Light: "#F8FAFC", Dark: "#181825", Aliases: []string{"bg-secondary"}},
+	{Name: SidebarForeground, Group: GroupWorkbench, Description: "Side bar text", Light: "#334155", Dark: "#BAC2DE"},
+	{Name: ActivityBarBackground, Group: GroupWorkbench, Description: "Activity bar background", Light: "#F1F5F9", Dark: "#11111B", Aliases: []string{"bg-tertiary"}},
+	{Name: ActivityBarForeground, Group: GroupWorkbench, Description: "Activity bar icons", Light: "#475569", Dark: "#A6ADC8"},
+	{Name: TitleBarBackground, Group: GroupWorkbench, Description: "Title bar background", Light: "#F1F5F9", Dark: "#11111B", Aliases: []string{"bg-header"}},
+	{Name: TitleBarForeground, Group: GroupWorkbench, Description: "Title bar text", Light: "#0F172A", Dark: "#CDD6F4"},
+	{Name: StatusBarBackground, Group: GroupWorkbench, Description: "Status bar background", Light: "#2563EB", Dark: "#89B4FA", Aliases: []string{"bg-accent"}},
+	{Name: StatusBarForeground, Group: GroupWorkbench, Description: "Status bar text", Light: "#FFFFFF", Dark: "#1E1E2E"},
+
+	{Name: EditorBackground, Group: GroupEditor, Description: "Editor background", Light: "#FFFFFF", Dark: "#1E1E2E"},
+	{Name: EditorForeground, Group: GroupEditor, Description: "Editor default text", Light: "#1E1E1E", Dark: "#CDD6F4"},
+	{Name: EditorCursor, Group: GroupEditor, Description: "Editor cursor (caret)", Light: "#1E1E1E", Dark: "#F5E0DC", Aliases: []string{"caret"}},
+	{Name: EditorSelection, Group: GroupEditor, Description: "Editor selection background", Light: "#BFDBFE", Dark: "#45475A", Aliases: []string{"bg-selection"}},
+	{Name: EditorLineHighlight, Group: GroupEditor, Description: "Background of the cursor line", Light: "#F1F5F9", Dark: "#2A2B3C", Aliases: []string{"bg-line-highlight"}},
+	{Name: ListSelection, Group: GroupEditor, Description: "Selected item in lists and trees", Light: "#DBEAFE", Dark: "#313244"},
+
+	{Name: InputBackground, Group: GroupControls, Description: "Input field background", Light: "#FFFFFF", Dark: "#313244"},
+	{Name: InputForeground, Group: GroupControls, Description: "Input field text", Light: "#1E1E1E", Dark: "#CDD6F4"},
+	{Name: InputBorder, Group: GroupControls, Description: "Input field border", Light: "#CBD5E1", Dark: "#45475A"},
+	{Name: ButtonBackground, Group: GroupControls, Description: "Primary button background", Light: "#2563EB", Dark: "#89B4FA"},
+	{Name: ButtonForeground, Group: GroupControls, Description: "Primary button text", Light: "#FFFFFF", Dark: "#1E1E2E"},
+
+	{Name: Error, Group: GroupStatus, Description: "Errors and destructive actions", Light: "#DC2626", Dark: "#F38BA8"},
+	{Name: Warning, Group: GroupStatus, Description: "Warnings", Light: "#D97706", Dark: "#FAB387"},
+	{Name: Success, Group: GroupStatus, Description: "Success states", Light: "#16A34A", Dark: "#A6E3A1"},
+	{Name: Info, Group: GroupStatus, Description: "Informational messages", Light: "#2563EB", Dark: "#89B4FA"},
+}
+
+// byName indexes registry entries by canonical name and by alias.
+var byName = func() map[string]int {
+	idx := make(map[string]int, len(registry)*2)
+	for i, k := range registry {
+		idx[k.Name] = i
+		for _, a := range k.Aliases {
+			idx[a] = i
+		}
+	}
+	return idx
+}()
+
+// All returns every canonical key in registry order.
+func All() []Key {
+	out := make([]Key, len(registry))
+	copy(out, registry)
+	return out
+}
+
+// Names returns the canonical key names in registry order.
+func Names() []string {
+	out := make([]string, len(registry))
+	for i, k := range registry {
+		out[i] = k.Name
+	}
+	return out
+}
+
+// Lookup returns the key registered under name or one of its aliases.
+func Lookup(name string) (Key, bool) {
+	i, ok := byName[name]
+	if !ok {
+		return Key{}, false
+	}
+	return registry[i], true
+}
+
+// Canonical returns the canonical name for name or an alias of it, and
+// "" if name is not a registered key.
+func Canonical(name string) string {
+	if k, ok := Lookup(name); ok {
+		return k.Name
+	}
+	return ""
+}
+
+// IsKnown reports whether name is a canonical key, an alias, or a
+// preserved "raw." source key.
+func IsKnown(name string) bool {
+	if strings.HasPrefix(name, RawPrefix) {
+		return true
+	}
+	_, ok := byName[name]
+	return ok
+}
+
+// Defaults returns the default palette for a theme type. Types other
+// than "light" use the dark palette.
+func Defaults(themeType string) map[string]string {
+	out := make(map[string]string, len(registry))
+	for _, k := range registry {
+		if themeType == "light" {
+			out[k.Name] = k.Light
+		} else {
+			out[k.Name] = k.Dark
+		}
+	}
+	return out
+}
+
+// Normalize returns a copy of colors with aliases rewritten to their
+// canonical names. When both an alias and its canonical key are present
+// the canonical value wins. Unknown keys are kept unchanged.
+func Normalize(colors map[string]string) map[string]string {
+	out := make(map[string]string, len(colors))
+	var aliases []string
+	for key, value := range colors {
+		canonical := Canonical(key)
+		if canonical == "" || canonical == key {
+			out[key] = value
+			continue
+		}
+		aliases = append(aliases, key)
+	}
+	// Apply aliases in a stable order so results are deterministic when
+	// two aliases of the same key are present.
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		canonical := Canonical(alias)
+		if _, ok := out[canonical]; !ok {
+			out[canonical] = colors[alias]
+		}
+	}
+	return out
+}
@@ -0,0 +1,294 @@
+// Package contrast audits theme colors for legibility using WCAG 2.x
+// contrast ratios and, optionally, APCA lightness contrast.
+package contrast
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// Conformance levels.
+const (
+	LevelAA  = "AA"
+	LevelAAA = "AAA"
+)
+
+// Pair kinds. Text must meet the WCAG 1.4.3/1.4.6 text thresholds; UI
+// components only need the 1.4.11 non-text threshold.
+const (
+	KindText = "text"
+	KindUI   = "ui"
+)
+
+// Pair is a foreground color key that is drawn on a background key.
+type Pair struct {
+	Foreground string `json:"foreground"`
+	Background string `json:"background"`
+	Kind       string `json:"kind"`
+}
+
+// Pairs lists the color key combinations the UI renders together.
+var Pairs = []Pair{
+	{colorkeys.Foreground, colorkeys.Background, KindText},
+	{colorkeys.Secondary, colorkeys.Background, KindText},
+	{colorkeys.Primary, colorkeys.Background, KindText},
+	{colorkeys.EditorForeground, colorkeys.EditorBackground, KindText},
+	{colorkeys.EditorForeground, colorkeys.EditorLineHighlight, KindText},
+	{colorkeys.EditorForeground, colorkeys.EditorSelection, KindText},
+	{colorkeys.SidebarForeground, colorkeys.SidebarBackground, KindText},
+	{colorkeys.ActivityBarForeground, colorkeys.ActivityBarBackground, KindUI},
+	{colorkeys.TitleBarForeground, colorkeys.TitleBarBackground, KindText},
+	{colorkeys.StatusBarForeground, colorkeys.StatusBarBackground, KindText},
+	{colorkeys.InputForeground, colorkeys.InputBackground, KindText},
+	{colorkeys.ButtonForeground, colorkeys.ButtonBackground, KindText},
+	{colorkeys.Error, colorkeys.EditorBackground, KindText},
+	{colorkeys.Warning, colorkeys.EditorBackground, KindText},
+	{colorkeys.Success, colorkeys.EditorBackground, KindText},
+	{colorkeys.Info, colorkeys.EditorBackground, KindText},
+	{colorkeys.EditorCursor, colorkeys.EditorBackground, KindUI},
+	{colorkeys.FocusBorder, colorkeys.Background, KindUI},
+	{colorkeys.InputBorder, colorkeys.InputBackground, KindUI},
+}
+
+// Required returns the minimum WCAG contrast ratio for kind at level.
+func Required(kind, level string) float64 {
+	if kind == KindUI {
+		return 3
+	}
+	if level == LevelAAA {
+		return 7
+	}
+	return 4.5
+}
+
+// Options controls an audit.
+type Options struct {
+	// APCA adds the APCA lightness contrast (Lc) to every check.
+	APCA bool
+}
+
+// Check is the result for one foreground/background combination.
+type Check struct {
+	// Foreground and Background name what was compared: a color key, or
+	// a JSON path such as "$.token_colors[3].settings.foreground".
+	Foreground      string  `json:"foreground"`
+	Background      string  `json:"background"`
+	ForegroundColor string  `json:"foreground_color"`
+	BackgroundColor string  `json:"background_color"`
+	Kind            string  `json:"kind"`
+	Ratio           float64 `json:"ratio"`
+	AA              bool    `json:"aa"`
+	AAA             bool    `json:"aaa"`
+	APCA            float64 `json:"apca,omitempty"`
+}
+
+// Passes reports whether the check meets level.
+func (c Check) Passes(level string) bool {
+	if level == LevelAAA {
+		return c.AAA
+	}
+	return c.AA
+}
+
+// Summary counts checks by outcome.
+type Summary struct {
+	Total   int `json:"total"`
+	PassAA  int `json:"pass_aa"`
+	PassAAA int `json:"pass_aaa"`
+}
+
+// Report is the contrast audit of one theme.
+type Report struct {
+	ThemeID string  `json:"theme_id"`
+	Checks  []Check `json:"checks"`
+	Summary Summary `json:"summary"`
+	// Skipped lists pairs that could not be checked because a color was
+	// missing or unparseable.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Audit checks every known pair in theme and every token foreground
+// against the editor background. Token rules whose foreground is
+// overridden by a later rule with the same scopes are never rendered and
+// are not checked.
+func Audit(theme *types.ThemeDef, opts Options) Report {
+	report := Report{ThemeID: theme.ID, Checks: []Check{}}
+
+	for _, p := range Pairs {
+		bg, ok := background(theme.Colors, p.Background)
+		if !ok {
+			report.Skipped = append(report.Skipped, p.Foreground+" on "+p.Background)
+			continue
+		}
+		fg, ok := parse(theme.Colors[p.Foreground])
+		if !ok {
+			report.Skipped = append(report.Skipped, p.Foreground+" on "+p.Background)
+			continue
+		}
+		report.Checks = append(report.Checks, check(p.Foreground, p.Background, fg, bg, p.Kind, opts))
+	}
+
+	editorBg, ok := background(theme.Colors, colorkeys.EditorBackground)
+	if ok {
+		for i, tc := range theme.TokenColors {
+			value, has := tc.Settings["foreground"]
+			if !has || shadowed(theme.TokenColors, i) {
+				continue
+			}
+			path := fmt.Sprintf("$.token_colors[%d].settings.foreground", i)
+			fg, ok := parse(value)
+			if !ok {
+				report.Skipped = append(report.Skipped, path)
+				continue
+			}
+			report.Checks = append(report.Checks, check(path, colorkeys.EditorBackground, fg, editorBg, KindText, opts))
+		}
+	}
+
+	for _, c := range report.Checks {
+		report.Summary.Total++
+		if c.AA {
+			report.Summary.PassAA++
+		}
+		if c.AAA {
+			report.Summary.PassAAA++
+		}
+	}
+	return report
+}
+
+// shadowed reports whether a later token rule with the same scopes also
+// sets a foreground, so rule i's foreground never applies.
+func shadowed(rules []types.TokenColor, i int) bool {
+	scopes := scopeSet(rules[i].Scope)
+	for _, later := range rules[i+1:] {
+		if _, has := later.Settings["foreground"]; !has || len(later.Scope) != len(rules[i].Scope) {
+			continue
+		}
+		same := true
+		for _, sc := range later.Scope {
+			if !scopes[strings.TrimSpace(sc)] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return true
+		}
+	}
+	return false
+}
+
+func scopeSet(scopes []string) map[string]bool {
+	set := make(map[string]bool, len(scopes))
+	for _, sc := range scopes {
+		set[strings.TrimSpace(sc)] = true
+	}
+	return set
+}
+
+func check(fgName, bgName string, fg, bg color.Color, kind string, opts Options) Check {
+	fg = Composite(fg, bg)
+	ratio := Ratio(fg, bg)
+	c := Check{
+		Foreground:      fgName,
+		Background:      bgName,
+		ForegroundColor: fg.Hex(),
+		BackgroundColor: bg.Hex(),
+		Kind:            kind,
+		Ratio:           math.Round(ratio*100) / 100,
+		AA:              ratio >= Required(kind, LevelAA),
+		AAA:             ratio >= Required(kind, LevelAAA),
+	}
+	if opts.APCA {
+		c.APCA = math.Round(APCA(fg, bg)*10) / 10
+	}
+	return c
+}
+
+// background resolves a background key to an opaque color. Translucent
+// backgrounds are composited over the theme's main background, which is
+// itself composited over black if it is translucent.
+func background(colors map[string]string, key string) (color.Color, bool) {
+	bg, ok := parse(colors[key])
+	if !ok || bg.A >= 1 {
+		return bg, ok
+	}
+	base := color.Color{A: 1}
+	if key != colorkeys.Background {
+		if main, ok := parse(colors[colorkeys.Background]); ok {
+			base = Composite(main, base)
+		}
+	}
+	return Composite(bg, base), true
+}
+
+func parse(value string) (color.Color, bool) {
+	if value == "" {
+		return color.Color{}, false
+	}
+	c, err := color.Parse(value)
+	return c, err == nil
+}
+
+// Composite draws fg over an opaque bg and returns the opaque result.
+func Composite(fg, bg color.Color) color.Color {
+	if fg.A >= 1 {
+		return fg
+	}
+	return color.Color{
+		R: fg.R*fg.A + bg.R*(1-fg.A),
+		G: fg.G*fg.A + bg.G*(1-fg.A),
+		B: fg.B*fg.A + bg.B*(1-fg.A),
+		A: 1,
+	}
+}
+
+// Ratio returns the WCAG 2.x contrast ratio between two opaque colors,
+// from 1 to 21.
+func Ratio(a, b color.Color) float64 {
+	la, lb := a.Luminance(), b.Luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// APCA returns the APCA-W3 (0.0.98G) lightness contrast Lc of text on
+// background. Positive values are dark text on a light background,
+// negative values light text on a dark background; |Lc| 75 is roughly
+// comparable to WCAG 4.5:1 for body text.
+func APCA(text, bg color.Color) float64 {
+	yt, yb := apcaY(text), apcaY(bg)
+	if math.Abs(yb-yt) < 0.0005 {
+		return 0
+	}
+
+	var lc float64
+	if yb > yt {
+		s := (math.Pow(yb, 0.56) - math.Pow(yt, 0.57)) * 1.14
+		if s >= 0.1 {
+			lc = s - 0.027
+		}
+	} else {
+		s := (math.Pow(yb, 0.65) - math.Pow(yt, 0.62)) * 1.14
+		if s <= -0.1 {
+			lc = s + 0.027
+		}
+	}
+	return lc * 100
+}
+
+// apcaY is the APCA screen luminance estimate with its black soft clamp.
+func apcaY(c color.Color) float64 {
+	y := 0.2126729*math.Pow(c.R, 2.4) + 0.7151522*math.Pow(c.G, 2.4) + 0.0721750*math.Pow(c.B, 2.4)
+	if y < 0.022 {
+		y += math.Pow(0.022-y, 1.414)
+	}
+	return y
+}
@@ -0,0 +1,220 @@
+package contrast
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// ParseLevel returns the conformance level named by s. An empty string
+// selects AA; matching is case-insensitive.
+func ParseLevel(s string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "", LevelAA:
+		return LevelAA, nil
+	case LevelAAA:
+		return LevelAAA, nil
+	default:
+		return "", fmt.Errorf("invalid contrast level %q: must be AA or AAA", s)
+	}
+}
+
+// Fix is one foreground color changed by Repair.
+type Fix struct {
+	// Target is the color key or token path that was changed, named as
+	// in Check.Foreground.
+	Target string `json:"target"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	// Before and After are the lowest contrast ratios of the color
+	// against the backgrounds it is drawn on.
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	// Met is false when no lightness reaches the level, for example on a
+	// mid-gray background; To is then the closest achievable color.
+	Met bool `json:"met"`
+}
+
+// target is a background a foreground must contrast with.
+type target struct {
+	bg  color.Color
+	min float64
+}
+
+// Repair returns a copy of theme in which every foreground that fails
+// level against one of its backgrounds has been adjusted, along with the
+// fixes made. Colors are moved along OKLCH lightness only, keeping hue
+// and as much chroma as the sRGB gamut allows, by the smallest amount
+// that satisfies all backgrounds the color is drawn on. Token
+// foregrounds are repaired against the editor background, as in Audit.
+func Repair(theme *types.ThemeDef, level string) (*types.ThemeDef, []Fix) {
+	out := copyTheme(theme)
+	fixes := []Fix{}
+
+	var order []string
+	targets := map[string][]target{}
+	for _, p := range Pairs {
+		bg, ok := background(theme.Colors, p.Background)
+		if !ok {
+			continue
+		}
+		if _, ok := parse(theme.Colors[p.Foreground]); !ok {
+			continue
+		}
+		if _, seen := targets[p.Foreground]; !seen {
+			order = append(order, p.Foreground)
+		}
+		targets[p.Foreground] = append(targets[p.Foreground], target{bg, Required(p.Kind, level)})
+	}
+	for _, key := range order {
+		fg, _ := parse(theme.Colors[key])
+		if fix, changed := repair(key, fg, targets[key]); changed {
+			out.Colors[key] = fix.To
+			fixes = append(fixes, fix)
+		}
+	}
+
+	editorBg, ok := background(theme.Colors, colorkeys.EditorBackground)
+	if !ok {
+		return out, fixes
+	}
+	editor := []target{{editorBg, Required(KindText, level)}}
+	for i, tc := range theme.TokenColors {
+		fg, ok := parse(tc.Settings["foreground"])
+		if !ok || shadowed(theme.TokenColors, i) {
+			continue
+		}
+		path := fmt.Sprintf("$.token_colors[%d].settings.foreground", i)
+		if fix, changed := repair(path, fg, editor); changed {
+			out.TokenColors[i].Settings["foreground"] = fix.To
+			fixes = append(fixes, fix)
+		}
+	}
+	return out, fixes
+}
+
+// repair adjusts fg until it meets every target. It reports false when
+// fg already passes.
+func repair(name string, fg color.Color, targets []target) (Fix, bool) {
+	before := worst(fg, targets)
+	if passes(fg, targets) {
+		return Fix{}, false
+	}
+
+	fixed, met := adjust(fg, targets)
+	if !met && fg.A < 1 {
+		// Translucency may cap the reachable contrast; try opaque.
+		opaque := fg
+		opaque.A = 1
+		fixed, met = adjust(opaque, targets)
+	}
+	return Fix{
+		Target: name,
+		From:   fg.Hex(),
+		To:     fixed.Hex(),
+		Before: math.Round(before*100) / 100,
+		After:  math.Round(worst(fixed, targets)*100) / 100,
+		Met:    met,
+	}, true
+}
+
+// Adjust returns fg moved along OKLCH lightness until it reaches ratio
+// min against the opaque background bg. It reports false when no
+// lightness does, in which case the closest color is returned.
+func Adjust(fg, bg color.Color, min float64) (color.Color, bool) {
+	targets := []target{{bg, min}}
+	if passes(fg, targets) {
+		return fg, true
+	}
+	return adjust(fg, targets)
+}
+
+// adjust searches OKLCH lightness in both directions for the closest
+// passing color. When neither direction can pass, it returns whichever
+// lightness extreme comes closest.
+func adjust(fg color.Color, targets []target) (color.Color, bool) {
+	l, chroma, h := fg.OKLCH()
+	at := func(l float64) color.Color {
+		return rounded(color.FromOKLCH(l, chroma, h, fg.A))
+	}
+
+	var best color.Color
+	bestDelta := math.Inf(1)
+	for _, end := range []float64{1, 0} {
+		if !passes(at(end), targets) {
+			continue
+		}
+		lo, hi := l, end
+		for i := 0; i < 32; i++ {
+			mid := (lo + hi) / 2
+			if passes(at(mid), targets) {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+		if d := math.Abs(hi - l); d < bestDelta {
+			best, bestDelta = at(hi), d
+		}
+	}
+	if !math.IsInf(bestDelta, 1) {
+		return best, true
+	}
+
+	light, dark := at(1), at(0)
+	if worst(light, targets) >= worst(dark, targets) {
+		return light, false
+	}
+	return dark, false
+}
+
+// rounded snaps c to the precision of its hex form, so that the checked
+// color is exactly the one stored.
+func rounded(c color.Color) color.Color {
+	return color.MustParse(c.Hex())
+}
+
+func passes(fg color.Color, targets []target) bool {
+	for _, t := range targets {
+		if Ratio(Composite(fg, t.bg), t.bg) < t.min {
+			return false
+		}
+	}
+	return true
+}
+
+// worst returns the lowest ratio of fg against targets.
+func worst(fg color.Color, targets []target) float64 {
+	low := math.Inf(1)
+	for _, t := range targets {
+		low = math.Min(low, Ratio(Composite(fg, t.bg), t.bg))
+	}
+	return low
+}
+
+func copyTheme(theme *types.ThemeDef) *types.ThemeDef {
+	out := *theme
+	out.Colors = make(map[string]string, len(theme.Colors))
+	for k, v := range theme.Colors {
+		out.Colors[k] = v
+	}
+	out.TokenColors = make([]types.TokenColor, len(theme.TokenColors))
+	for i, tc := range theme.TokenColors {
+		out.TokenColors[i] = tc
+		out.TokenColors[i].Scope = append([]string(nil), tc.Scope...)
+		out.TokenColors[i].Settings = make(map[string]string, len(tc.Settings))
+		for k, v := range tc.Settings {
+			out.TokenColors[i].Settings[k] = v
+		}
+	}
+	if theme.Terminal != nil {
+		terminal := *theme.Terminal
+		out.Terminal = &terminal
+	}
+	out.Synthesized = append([]string(nil), theme.Synthesized...)
+	return &out
+}
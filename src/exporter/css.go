@@ -0,0 +1,155 @@
+package exporter
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// CSS scopes.
+const (
+	// ScopeRoot applies a theme's properties to :root.
+	ScopeRoot = "root"
+	// ScopeTheme applies them to [data-theme="<id>"], so several themes
+	// can coexist in one stylesheet.
+	ScopeTheme = "theme"
+)
+
+// DefaultCSSPrefix prefixes every custom property name.
+const DefaultCSSPrefix = "orchestra"
+
+// CSSOptions controls ExportCSS.
+type CSSOptions struct {
+	// Scope is ScopeRoot (default) or ScopeTheme.
+	Scope string
+	// Prefix replaces DefaultCSSPrefix in property names; see
+	// ValidCSSPrefix.
+	Prefix string
+}
+
+// ValidCSSPrefix reports whether prefix can start a custom property
+// name: empty, for the default, or ASCII letters, digits, "-" and "_".
+func ValidCSSPrefix(prefix string) bool {
+	for _, r := range prefix {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// CSSProperty returns the custom property name for a color key, e.g.
+// "--orchestra-editor-line-highlight" for "editor.lineHighlight". An
+// empty or invalid prefix is replaced by DefaultCSSPrefix.
+func CSSProperty(prefix, key string) string {
+	if prefix == "" || !ValidCSSPrefix(prefix) {
+		prefix = DefaultCSSPrefix
+	}
+	var b strings.Builder
+	b.WriteString("--" + prefix + "-")
+	for i, r := range key {
+		switch {
+		case r == '.' || r == '_':
+			b.WriteByte('-')
+		case unicode.IsUpper(r):
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ExportCSS renders theme as a CSS rule of custom properties, one per
+// canonical color key, plus a color-scheme declaration.
+func ExportCSS(theme *types.ThemeDef, opts CSSOptions) []byte {
+	selector := ":root"
+	if opts.Scope == ScopeTheme {
+		selector = themeSelector(theme.ID)
+	}
+	var buf bytes.Buffer
+	writeCSSRule(&buf, selector, theme, opts.Prefix, "")
+	return buf.Bytes()
+}
+
+// Stylesheet renders prefers-color-scheme media queries that apply light
+// and dark to :root for pages that do not pick a theme, followed by every
+// theme scoped to [data-theme="<id>"]. Both selectors have the same
+// specificity, so the later theme rules win over the defaults on an
+// element that is both :root and themed. light and dark may be nil.
+func Stylesheet(themes []*types.ThemeDef, light, dark *types.ThemeDef, prefix string) []byte {
+	var buf bytes.Buffer
+	for _, m := range []struct {
+		scheme string
+		theme  *types.ThemeDef
+	}{{"light", light}, {"dark", dark}} {
+		if m.theme == nil {
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("@media (prefers-color-scheme: " + m.scheme + ") {\n")
+		writeCSSRule(&buf, ":root", m.theme, prefix, "  ")
+		buf.WriteString("}\n")
+	}
+	for _, theme := range themes {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		writeCSSRule(&buf, themeSelector(theme.ID), theme, prefix, "")
+	}
+	return buf.Bytes()
+}
+
+func writeCSSRule(buf *bytes.Buffer, selector string, theme *types.ThemeDef, prefix, indent string) {
+	buf.WriteString(indent + selector + " {\n")
+	if scheme := colorScheme(theme); scheme != "" {
+		buf.WriteString(indent + "  color-scheme: " + scheme + ";\n")
+	}
+	for _, key := range colorkeys.Names() {
+		value, ok := theme.Colors[key]
+		if !ok {
+			continue
+		}
+		// Only emit values that parse as colors, so nothing stored in a
+		// theme can break out of the declaration.
+		normalized, err := color.Normalize(value)
+		if err != nil {
+			continue
+		}
+		buf.WriteString(indent + "  " + CSSProperty(prefix, key) + ": " + normalized + ";\n")
+	}
+	buf.WriteString(indent + "}\n")
+}
+
+func themeSelector(id string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(id)
+	return `[data-theme="` + escaped + `"]`
+}
+
+// colorScheme returns the CSS color-scheme value for theme.
+func colorScheme(theme *types.ThemeDef) string {
+	switch theme.Type {
+	case "light":
+		return "light"
+	case "dark":
+		return "dark"
+	}
+	if bg, ok := firstColor(theme.Colors, []string{colorkeys.Background, colorkeys.EditorBackground}); ok {
+		if c, err := color.Parse(bg); err == nil {
+			if c.IsLight() {
+				return "light"
+			}
+			return "dark"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+// Package exporter serializes themes into Orchestra JSON and the file
+// formats of other editors and terminal emulators. It is the reverse of
+// the importer package.
+package exporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// Export formats.
+const (
+	FormatOrchestra = "orchestra"
+	FormatVSCode    = "vscode"
+	FormatTmTheme   = "tmtheme"
+	FormatCSS       = "css"
+
+	// Terminal emulator formats, which carry only the terminal palette.
+	FormatAlacritty       = "alacritty"
+	FormatKitty           = "kitty"
+	FormatWindowsTerminal = "windows-terminal"
+	FormatITermColors     = "itermcolors"
+	FormatXresources      = "xresources"
+)
+
+// ErrUnsupportedFormat is returned for export formats that do not exist.
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// Formats lists the supported export formats.
+func Formats() []string {
+	return append([]string{FormatOrchestra, FormatVSCode, FormatTmTheme, FormatCSS}, TerminalFormats()...)
+}
+
+// TerminalFormats lists the export formats of terminal emulators.
+func TerminalFormats() []string {
+	return []string{FormatAlacritty, FormatKitty, FormatWindowsTerminal, FormatITermColors, FormatXresources}
+}
+
+// IsTerminalFormat reports whether format is a terminal emulator format.
+func IsTerminalFormat(format string) bool {
+	for _, f := range TerminalFormats() {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Export serializes theme in format with default options. An empty
+// format selects Orchestra JSON.
+func Export(theme *types.ThemeDef, format string) ([]byte, error) {
+	switch format {
+	case "", FormatOrchestra:
+		return json.MarshalIndent(theme, "", "  ")
+	case FormatVSCode:
+		return ExportVSCode(theme)
+	case FormatTmTheme:
+		return ExportTmTheme(theme)
+	case FormatCSS:
+		return ExportCSS(theme, CSSOptions{}), nil
+	case FormatAlacritty:
+		return ExportAlacritty(theme)
+	case FormatKitty:
+		return ExportKitty(theme)
+	case FormatWindowsTerminal:
+		return ExportWindowsTerminal(theme)
+	case FormatITermColors:
+		return ExportITermColors(theme)
+	case FormatXresources:
+		return ExportXresources(theme)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// ContentType returns the MIME type of format.
+func ContentType(format string) string {
+	switch format {
+	case FormatTmTheme, FormatITermColors:
+		return "application/xml"
+	case FormatCSS:
+		return "text/css; charset=utf-8"
+	case FormatAlacritty:
+		return "application/toml"
+	case FormatKitty, FormatXresources:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// firstColor returns the value of the first of keys present in colors.
+func firstColor(colors map[string]string, keys []string) (string, bool) {
+	for _, key := range keys {
+		if value, ok := colors[key]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// sameColor reports whether a and b parse to the same color.
+func sameColor(a, b string) bool {
+	na, errA := color.Normalize(a)
+	nb, errB := color.Normalize(b)
+	return errA == nil && errB == nil && na == nb
+}
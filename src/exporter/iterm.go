@@ -0,0 +1,55 @@
+package exporter
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// ExportITermColors serializes theme's terminal palette as an iTerm2
+// .itermcolors property list with sRGB components. Other colors preserved
+// by ImportITermColors, such as the bold or link color, are restored for
+// themes imported from iTerm2. Keys are sorted, as iTerm2 writes them.
+func ExportITermColors(theme *types.ThemeDef) ([]byte, error) {
+	colors := make(map[string]color.Color)
+	if theme.Source == "itermcolors" {
+		for key, value := range theme.Colors {
+			name, ok := strings.CutPrefix(key, colorkeys.RawPrefix)
+			if !ok {
+				continue
+			}
+			if c, err := color.Parse(value); err == nil {
+				colors[name] = c
+			}
+		}
+	}
+
+	p := terminalPalette(theme)
+	for key, field := range importer.TerminalColorMap("itermcolors") {
+		if c, err := color.Parse(*p.Field(field)); err == nil {
+			colors[key] = c
+		}
+	}
+
+	keys := make([]string, 0, len(colors))
+	for key := range colors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var root plistDict
+	for _, key := range keys {
+		c := colors[key]
+		root.set(key, plistDict{
+			{Key: "Alpha Component", Value: c.A},
+			{Key: "Blue Component", Value: c.B},
+			{Key: "Color Space", Value: "sRGB"},
+			{Key: "Green Component", Value: c.G},
+			{Key: "Red Component", Value: c.R},
+		})
+	}
+	return marshalPlist(root)
+}
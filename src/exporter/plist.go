@@ -0,0 +1,89 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// plistDict is a plist dictionary whose entries keep their insertion
+// order, which tmTheme readers and humans both expect.
+type plistDict []plistEntry
+
+// plistEntry is one key of a plistDict. Value is a string, a float64, a
+// plistDict or a []plistDict.
+type plistEntry struct {
+	Key   string
+	Value any
+}
+
+// set appends key unless value is empty.
+func (d *plistDict) set(key string, value any) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return
+		}
+	case plistDict:
+		if len(v) == 0 {
+			return
+		}
+	}
+	*d = append(*d, plistEntry{Key: key, Value: value})
+}
+
+const plistHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+`
+
+// marshalPlist encodes root as an XML property list, indented with tabs.
+func marshalPlist(root plistDict) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(plistHeader)
+	if err := writePlistValue(&buf, root, 0); err != nil {
+		return nil, err
+	}
+	buf.WriteString("</plist>\n")
+	return buf.Bytes(), nil
+}
+
+func writePlistValue(buf *bytes.Buffer, value any, depth int) error {
+	indent := strings.Repeat("\t", depth)
+	switch v := value.(type) {
+	case string:
+		buf.WriteString(indent + "<string>")
+		if err := xml.EscapeText(buf, []byte(v)); err != nil {
+			return err
+		}
+		buf.WriteString("</string>\n")
+	case float64:
+		buf.WriteString(indent + "<real>" + strconv.FormatFloat(v, 'g', -1, 64) + "</real>\n")
+	case plistDict:
+		buf.WriteString(indent + "<dict>\n")
+		for _, entry := range v {
+			buf.WriteString(indent + "\t<key>")
+			if err := xml.EscapeText(buf, []byte(entry.Key)); err != nil {
+				return err
+			}
+			buf.WriteString("</key>\n")
+			if err := writePlistValue(buf, entry.Value, depth+1); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(indent + "</dict>\n")
+	case []plistDict:
+		buf.WriteString(indent + "<array>\n")
+		for _, d := range v {
+			if err := writePlistValue(buf, d, depth+1); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(indent + "</array>\n")
+	default:
+		return fmt.Errorf("unsupported plist value %T", value)
+	}
+	return nil
+}
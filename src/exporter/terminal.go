@@ -0,0 +1,208 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// terminalEditorKeys are the canonical keys a palette's special colors
+// fall back to when the palette leaves them unset.
+var terminalEditorKeys = map[string][]string{
+	"background": {colorkeys.EditorBackground, colorkeys.Background},
+	"foreground": {colorkeys.EditorForeground, colorkeys.Foreground},
+	"cursor":     {colorkeys.EditorCursor},
+	"selection":  {colorkeys.EditorSelection},
+}
+
+// terminalPalette returns theme's terminal palette with every color
+// opaque, as terminals expect: translucent colors are blended over the
+// background. Unset background, foreground, cursor and selection colors
+// are taken from the editor; other unset fields stay empty and are
+// omitted by the exporters. Themes read through the service always have
+// a complete palette.
+func terminalPalette(theme *types.ThemeDef) *types.TerminalPalette {
+	p := &types.TerminalPalette{}
+	if theme.Terminal != nil {
+		*p = *theme.Terminal
+	}
+	for field, keys := range terminalEditorKeys {
+		if value := p.Field(field); *value == "" {
+			*value, _ = firstColor(theme.Colors, keys)
+		}
+	}
+
+	bg, err := color.Parse(p.Background)
+	if err != nil {
+		bg = color.Color{A: 1}
+	}
+	bg.A = 1
+	for _, name := range types.TerminalColorNames {
+		value := p.Field(name)
+		c, err := color.Parse(*value)
+		if err != nil {
+			*value = ""
+			continue
+		}
+		if c.A < 1 {
+			c = color.Mix(bg, color.Color{R: c.R, G: c.G, B: c.B, A: 1}, c.A)
+		}
+		*value = c.Hex()
+	}
+	return p
+}
+
+// commentText flattens s onto one line for use in a comment.
+func commentText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// ExportAlacritty serializes theme's terminal palette as the colors
+// section of an Alacritty TOML config.
+func ExportAlacritty(theme *types.ThemeDef) ([]byte, error) {
+	p := terminalPalette(theme)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", commentText(theme.Name))
+
+	section := func(table string, entries ...[2]string) {
+		var body bytes.Buffer
+		for _, e := range entries {
+			if e[1] != "" {
+				fmt.Fprintf(&body, "%s = %q\n", e[0], e[1])
+			}
+		}
+		if body.Len() > 0 {
+			fmt.Fprintf(&buf, "\n[%s]\n", table)
+			buf.Write(body.Bytes())
+		}
+	}
+	section("colors.primary", [2]string{"background", p.Background}, [2]string{"foreground", p.Foreground})
+	section("colors.cursor", [2]string{"text", p.Background}, [2]string{"cursor", p.Cursor})
+	section("colors.selection", [2]string{"text", "CellForeground"}, [2]string{"background", p.Selection})
+	for _, group := range []struct {
+		table  string
+		offset int
+	}{{"colors.normal", 0}, {"colors.bright", 8}} {
+		entries := make([][2]string, 8)
+		for i, name := range types.TerminalColorNames[:8] {
+			entries[i] = [2]string{name, *p.Field(types.TerminalColorNames[i+group.offset])}
+		}
+		section(group.table, entries...)
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportKitty serializes theme's terminal palette as a kitty color scheme
+// with the "## name:" metadata kitty themes use.
+func ExportKitty(theme *types.ThemeDef) ([]byte, error) {
+	p := terminalPalette(theme)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "## name: %s\n", commentText(theme.Name))
+	if theme.Author != "" {
+		fmt.Fprintf(&buf, "## author: %s\n", commentText(theme.Author))
+	}
+	if theme.Description != "" {
+		fmt.Fprintf(&buf, "## blurb: %s\n", commentText(theme.Description))
+	}
+	buf.WriteString("\n")
+
+	set := func(option, value string) {
+		if value != "" {
+			fmt.Fprintf(&buf, "%s %s\n", option, value)
+		}
+	}
+	set("background", p.Background)
+	set("foreground", p.Foreground)
+	set("cursor", p.Cursor)
+	set("cursor_text_color", p.Background)
+	set("selection_background", p.Selection)
+	buf.WriteString("\n")
+	for i, value := range p.ANSI() {
+		set(fmt.Sprintf("color%d", i), value)
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportXresources serializes theme's terminal palette as X resources
+// bound to every client ("*.").
+func ExportXresources(theme *types.ThemeDef) ([]byte, error) {
+	p := terminalPalette(theme)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "! %s\n\n", commentText(theme.Name))
+
+	set := func(resource, value string) {
+		if value != "" {
+			fmt.Fprintf(&buf, "*.%s: %s\n", resource, value)
+		}
+	}
+	set("background", p.Background)
+	set("foreground", p.Foreground)
+	set("cursorColor", p.Cursor)
+	set("highlightColor", p.Selection)
+	buf.WriteString("\n")
+	for i, value := range p.ANSI() {
+		set(fmt.Sprintf("color%d", i), value)
+	}
+	return buf.Bytes(), nil
+}
+
+// windowsTerminalScheme is a Windows Terminal color scheme, in the order
+// Windows Terminal writes its properties.
+type windowsTerminalScheme struct {
+	Name                string `json:"name"`
+	Background          string `json:"background,omitempty"`
+	Foreground          string `json:"foreground,omitempty"`
+	CursorColor         string `json:"cursorColor,omitempty"`
+	SelectionBackground string `json:"selectionBackground,omitempty"`
+	Black               string `json:"black,omitempty"`
+	Red                 string `json:"red,omitempty"`
+	Green               string `json:"green,omitempty"`
+	Yellow              string `json:"yellow,omitempty"`
+	Blue                string `json:"blue,omitempty"`
+	Purple              string `json:"purple,omitempty"`
+	Cyan                string `json:"cyan,omitempty"`
+	White               string `json:"white,omitempty"`
+	BrightBlack         string `json:"brightBlack,omitempty"`
+	BrightRed           string `json:"brightRed,omitempty"`
+	BrightGreen         string `json:"brightGreen,omitempty"`
+	BrightYellow        string `json:"brightYellow,omitempty"`
+	BrightBlue          string `json:"brightBlue,omitempty"`
+	BrightPurple        string `json:"brightPurple,omitempty"`
+	BrightCyan          string `json:"brightCyan,omitempty"`
+	BrightWhite         string `json:"brightWhite,omitempty"`
+}
+
+// ExportWindowsTerminal serializes theme's terminal palette as a Windows
+// Terminal color scheme, ready to paste into the "schemes" list of
+// settings.json.
+func ExportWindowsTerminal(theme *types.ThemeDef) ([]byte, error) {
+	p := terminalPalette(theme)
+	return json.MarshalIndent(windowsTerminalScheme{
+		Name:                theme.Name,
+		Background:          p.Background,
+		Foreground:          p.Foreground,
+		CursorColor:         p.Cursor,
+		SelectionBackground: p.Selection,
+		Black:               p.Black,
+		Red:                 p.Red,
+		Green:               p.Green,
+		Yellow:              p.Yellow,
+		Blue:                p.Blue,
+		Purple:              p.Magenta,
+		Cyan:                p.Cyan,
+		White:               p.White,
+		BrightBlack:         p.BrightBlack,
+		BrightRed:           p.BrightRed,
+		BrightGreen:         p.BrightGreen,
+		BrightYellow:        p.BrightYellow,
+		BrightBlue:          p.BrightBlue,
+		BrightPurple:        p.BrightMagenta,
+		BrightCyan:          p.BrightCyan,
+		BrightWhite:         p.BrightWhite,
+	}, "", "  ")
+}
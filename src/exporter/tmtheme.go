@@ -0,0 +1,78 @@
+package exporter
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// tmGlobalKeys is the order of the mapped global settings in the output.
+var tmGlobalKeys = []string{"background", "foreground", "caret", "selection", "lineHighlight"}
+
+// tmTokenSettingKeys is the order of token rule settings in the output.
+var tmTokenSettingKeys = []string{"foreground", "background", "fontStyle"}
+
+// ExportTmTheme serializes theme as a TextMate .tmTheme property list:
+// a global settings dict followed by one dict per token color rule.
+// Global colors come from the canonical keys the tmTheme importer maps
+// them to, falling back to their "raw." values. Other global settings
+// preserved by ImportTmTheme, such as gutter or invisibles colors, are
+// restored for themes imported from tmTheme.
+func ExportTmTheme(theme *types.ThemeDef) ([]byte, error) {
+	globalMap := importer.TmGlobalColorMap()
+	// Raw keys are in the namespace of the format they were imported
+	// from, so only tmTheme ones are meaningful here.
+	fromTmTheme := theme.Source == "tmtheme"
+
+	var global plistDict
+	for _, key := range tmGlobalKeys {
+		var raw string
+		if fromTmTheme {
+			raw = theme.Colors[colorkeys.RawPrefix+key]
+		}
+		value, ok := firstColor(theme.Colors, globalMap[key])
+		if !ok || sameColor(raw, value) {
+			value = raw
+		}
+		global.set(key, value)
+	}
+	if fromTmTheme {
+		var extra []string
+		for key := range theme.Colors {
+			name, ok := strings.CutPrefix(key, colorkeys.RawPrefix)
+			if ok && globalMap[name] == nil {
+				extra = append(extra, name)
+			}
+		}
+		sort.Strings(extra)
+		for _, name := range extra {
+			global.set(name, theme.Colors[colorkeys.RawPrefix+name])
+		}
+	}
+
+	settings := []plistDict{{{Key: "settings", Value: global}}}
+	for _, tc := range theme.TokenColors {
+		var rule plistDict
+		rule.set("name", tc.Name)
+		rule.set("scope", strings.Join(tc.Scope, ", "))
+		var ruleSettings plistDict
+		for _, key := range tmTokenSettingKeys {
+			ruleSettings.set(key, tc.Settings[key])
+		}
+		rule = append(rule, plistEntry{Key: "settings", Value: ruleSettings})
+		settings = append(settings, rule)
+	}
+
+	var root plistDict
+	root.set("name", theme.Name)
+	root.set("author", theme.Author)
+	root.set("comment", theme.Description)
+	if theme.Type != "" && theme.ID != "" {
+		root.set("semanticClass", "theme."+theme.Type+"."+theme.ID)
+	}
+	root = append(root, plistEntry{Key: "settings", Value: settings})
+	return marshalPlist(root)
+}
@@ -0,0 +1,140 @@
+package exporter
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// vscodeThemeFile is the VS Code color theme JSON structure.
+type vscodeThemeFile struct {
+	Schema      string                  `json:"$schema"`
+	Name        string                  `json:"name"`
+	Type        string                  `json:"type"`
+	Colors      map[string]string       `json:"colors"`
+	TokenColors []vscodeTokenColorEntry `json:"tokenColors"`
+
+	SemanticHighlighting *bool          `json:"semanticHighlighting,omitempty"`
+	SemanticTokenColors  map[string]any `json:"semanticTokenColors,omitempty"`
+}
+
+// vscodeSemanticStyle is the object form of a semanticTokenColors value.
+type vscodeSemanticStyle struct {
+	Foreground    string `json:"foreground,omitempty"`
+	FontStyle     string `json:"fontStyle,omitempty"`
+	Bold          *bool  `json:"bold,omitempty"`
+	Italic        *bool  `json:"italic,omitempty"`
+	Underline     *bool  `json:"underline,omitempty"`
+	Strikethrough *bool  `json:"strikethrough,omitempty"`
+}
+
+// vscodeTokenColorEntry is a single token color rule. Scope is a string
+// for single-scope rules and an array otherwise.
+type vscodeTokenColorEntry struct {
+	Name     string            `json:"name,omitempty"`
+	Scope    any               `json:"scope,omitempty"`
+	Settings map[string]string `json:"settings"`
+}
+
+// ExportVSCode serializes theme as a VS Code color theme. Canonical keys
+// are mapped back through the importer's VS Code key map; keys preserved
+// under the "raw." prefix by ImportVSCode are restored, so an imported theme
+// round-trips with its unmapped keys intact. A mapped key takes its
+// canonical value, keeping the raw spelling only when both denote the
+// same color, so edits made in Orchestra are not lost; the terminal
+// palette is written to the "terminal.*" keys the same way. Semantic
+// token rules use the short string form when they only set a foreground.
+func ExportVSCode(theme *types.ThemeDef) ([]byte, error) {
+	file := vscodeThemeFile{
+		Schema:      "vscode://schemas/color-theme",
+		Name:        theme.Name,
+		Type:        vscodeType(theme),
+		Colors:      make(map[string]string),
+		TokenColors: []vscodeTokenColorEntry{},
+	}
+
+	// Raw keys are in the namespace of the format they were imported
+	// from, so only VS Code ones are meaningful here.
+	if theme.Source == "vscode" {
+		for key, value := range theme.Colors {
+			if vsKey, ok := strings.CutPrefix(key, colorkeys.RawPrefix); ok {
+				file.Colors[vsKey] = value
+			}
+		}
+	}
+	for vsKey, canonical := range importer.VSCodeColorMap() {
+		value, ok := firstColor(theme.Colors, canonical)
+		if !ok {
+			continue
+		}
+		if raw, ok := file.Colors[vsKey]; ok && sameColor(raw, value) {
+			continue
+		}
+		file.Colors[vsKey] = value
+	}
+	if theme.Terminal != nil {
+		for vsKey, field := range importer.TerminalColorMap("vscode") {
+			value := *theme.Terminal.Field(field)
+			if value == "" {
+				continue
+			}
+			if raw, ok := file.Colors[vsKey]; ok && sameColor(raw, value) {
+				continue
+			}
+			file.Colors[vsKey] = value
+		}
+	}
+
+	for _, tc := range theme.TokenColors {
+		entry := vscodeTokenColorEntry{
+			Name:     tc.Name,
+			Settings: make(map[string]string, len(tc.Settings)),
+		}
+		switch len(tc.Scope) {
+		case 0:
+		case 1:
+			entry.Scope = tc.Scope[0]
+		default:
+			entry.Scope = tc.Scope
+		}
+		for k, v := range tc.Settings {
+			entry.Settings[k] = v
+		}
+		file.TokenColors = append(file.TokenColors, entry)
+	}
+
+	file.SemanticHighlighting = theme.SemanticHighlighting
+	if len(theme.SemanticTokenColors) > 0 {
+		file.SemanticTokenColors = make(map[string]any, len(theme.SemanticTokenColors))
+	}
+	for _, sc := range theme.SemanticTokenColors {
+		if sc.Style == (types.SemanticTokenStyle{Foreground: sc.Style.Foreground}) {
+			file.SemanticTokenColors[sc.Selector] = sc.Style.Foreground
+			continue
+		}
+		file.SemanticTokenColors[sc.Selector] = vscodeSemanticStyle(sc.Style)
+	}
+
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// vscodeType returns the VS Code theme type for theme.
+func vscodeType(theme *types.ThemeDef) string {
+	switch theme.Type {
+	case "light":
+		return "light"
+	case "high-contrast":
+		if bg, ok := firstColor(theme.Colors, []string{colorkeys.EditorBackground, colorkeys.Background}); ok {
+			if c, err := color.Parse(bg); err == nil && c.IsLight() {
+				return "hc-light"
+			}
+		}
+		return "hc-black"
+	default:
+		return "dark"
+	}
+}
@@ -0,0 +1,304 @@
+// Package generator builds complete themes from a few seed colors.
+//
+// Every canonical color key, a default set of token colors and a terminal
+// palette are derived from a background, a foreground and an accent
+// color. Colors are placed in OKLCH so that hues stay recognizable and
+// lightness steps look even, and the result is passed through contrast
+// repair so that every text pair meets WCAG AA.
+package generator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/contrast"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// Seeds are the inputs of Generate.
+type Seeds struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Author      string `json:"author,omitempty"`
+	// Type is "light" or "dark". When empty it is inferred from the
+	// background.
+	Type       string `json:"type,omitempty"`
+	Background string `json:"background"`
+	Foreground string `json:"foreground"`
+	Accent     string `json:"accent"`
+}
+
+// Status color hues in OKLCH degrees.
+const (
+	hueError   = 25
+	hueWarning = 75
+	hueSuccess = 145
+	hueInfo    = 245
+)
+
+// ANSI terminal hues in OKLCH degrees; red, yellow, green and blue share
+// the status hues.
+const (
+	hueMagenta = 330
+	hueCyan    = 200
+)
+
+// tokenRole is a group of TextMate scopes colored alike. Hue is an
+// offset from the accent hue.
+type tokenRole struct {
+	name      string
+	scopes    []string
+	hue       float64
+	fontStyle string
+}
+
+var tokenRoles = []tokenRole{
+	{"Keyword", []string{"keyword", "storage.type", "storage.modifier"}, 0, ""},
+	{"Function", []string{"entity.name.function", "support.function", "meta.function-call"}, 60, ""},
+	{"Type", []string{"entity.name.type", "entity.name.class", "support.type", "support.class"}, 120, ""},
+	{"String", []string{"string", "string.quoted"}, 180, ""},
+	{"Number", []string{"constant.numeric", "constant.language", "constant.character"}, 240, ""},
+	{"Parameter", []string{"variable.parameter"}, 300, "italic"},
+	{"Tag", []string{"entity.name.tag"}, 30, ""},
+	{"Attribute", []string{"entity.other.attribute-name"}, 90, "italic"},
+}
+
+// Generate returns a complete theme built from seeds. Seed colors accept
+// any syntax color.Parse does. The foreground and accent may be adjusted
+// if they do not contrast enough with the background.
+func Generate(seeds Seeds) (*types.ThemeDef, error) {
+	bg, err := parseSeed("background", seeds.Background)
+	if err != nil {
+		return nil, err
+	}
+	fg, err := parseSeed("foreground", seeds.Foreground)
+	if err != nil {
+		return nil, err
+	}
+	accent, err := parseSeed("accent", seeds.Accent)
+	if err != nil {
+		return nil, err
+	}
+
+	var light bool
+	switch seeds.Type {
+	case "":
+		light = bg.IsLight()
+	case "light":
+		light = true
+	case "dark":
+	default:
+		return nil, fmt.Errorf("invalid theme type %q: must be light or dark", seeds.Type)
+	}
+	themeType := "dark"
+	if light {
+		themeType = "light"
+	}
+
+	// The accent is used as link text on the background and as the
+	// status bar and button color, so fix its contrast up front rather
+	// than letting repair split it into several shades.
+	accent, _ = contrast.Adjust(accent, bg, contrast.Required(contrast.KindText, contrast.LevelAA))
+
+	p := palette{bg: bg, fg: fg, accent: accent, light: light}
+	theme := &types.ThemeDef{
+		ID:          seeds.ID,
+		Name:        seeds.Name,
+		Description: seeds.Description,
+		Author:      seeds.Author,
+		Type:        themeType,
+		Source:      "generated",
+		Colors:      p.colors(),
+		TokenColors: p.tokenColors(),
+		Terminal:    p.terminal(),
+	}
+	if theme.Name == "" {
+		theme.Name = seeds.ID
+	}
+	if theme.Description == "" {
+		theme.Description = fmt.Sprintf("Generated from background %s, foreground %s and accent %s",
+			bg.Hex(), fg.Hex(), accent.Hex())
+	}
+
+	repaired, _ := contrast.Repair(theme, contrast.LevelAA)
+	return repaired, nil
+}
+
+func parseSeed(name, value string) (color.Color, error) {
+	if value == "" {
+		return color.Color{}, fmt.Errorf("%s color is required", name)
+	}
+	c, err := color.Parse(value)
+	if err != nil {
+		return color.Color{}, fmt.Errorf("invalid %s color: %w", name, err)
+	}
+	c.A = 1
+	return c, nil
+}
+
+// palette derives theme colors from the seeds.
+type palette struct {
+	bg, fg, accent color.Color
+	light          bool
+}
+
+// surface returns the background moved by dl in OKLCH lightness, towards
+// the foreground for positive dl.
+func (p palette) surface(dl float64) color.Color {
+	if p.light {
+		dl = -dl
+	}
+	l, c, h := p.bg.OKLCH()
+	return color.FromOKLCH(l+dl, c, h, 1)
+}
+
+// recessed returns the background darkened by dl in OKLCH lightness, for
+// chrome such as the sidebar that sits behind the editor in both light
+// and dark themes.
+func (p palette) recessed(dl float64) color.Color {
+	l, c, h := p.bg.OKLCH()
+	return color.FromOKLCH(l-dl, c, h, 1)
+}
+
+// hue returns a color at the given OKLCH hue, with lightness and chroma
+// suited to text on the theme background.
+func (p palette) hue(h, chroma float64) color.Color {
+	l := 0.78
+	if p.light {
+		l = 0.52
+	}
+	return color.FromOKLCH(l, chroma, math.Mod(h+360, 360), 1)
+}
+
+// on returns whichever of the background and foreground reads better on
+// top of c.
+func (p palette) on(c color.Color) color.Color {
+	if contrast.Ratio(p.bg, c) >= contrast.Ratio(p.fg, c) {
+		return p.bg
+	}
+	return p.fg
+}
+
+func (p palette) colors() map[string]string {
+	_, accentChroma, accentHue := p.accent.OKLCH()
+	secondary := color.Mix(p.fg, p.bg, 0.35)
+	border := p.surface(0.10)
+	selection := color.Mix(p.surface(0.08), p.accent, 0.25)
+
+	colors := map[string]color.Color{
+		colorkeys.Background:            p.bg,
+		colorkeys.Foreground:            p.fg,
+		colorkeys.Primary:               p.accent,
+		colorkeys.Secondary:             secondary,
+		colorkeys.Accent:                p.hue(accentHue+50, math.Max(accentChroma, 0.08)),
+		colorkeys.Border:                border,
+		colorkeys.FocusBorder:           p.accent,
+		colorkeys.SidebarBackground:     p.recessed(0.02),
+		colorkeys.SidebarForeground:     p.fg,
+		colorkeys.ActivityBarBackground: p.recessed(0.04),
+		colorkeys.ActivityBarForeground: secondary,
+		colorkeys.TitleBarBackground:    p.recessed(0.04),
+		colorkeys.TitleBarForeground:    p.fg,
+		colorkeys.StatusBarBackground:   p.accent,
+		colorkeys.StatusBarForeground:   p.on(p.accent),
+		colorkeys.EditorBackground:      p.bg,
+		colorkeys.EditorForeground:      p.fg,
+		colorkeys.EditorCursor:          p.accent,
+		colorkeys.EditorSelection:       selection,
+		colorkeys.EditorLineHighlight:   p.surface(0.03),
+		colorkeys.ListSelection:         selection,
+		colorkeys.InputBackground:       p.surface(0.04),
+		colorkeys.InputForeground:       p.fg,
+		colorkeys.InputBorder:           border,
+		colorkeys.ButtonBackground:      p.accent,
+		colorkeys.ButtonForeground:      p.on(p.accent),
+		colorkeys.Error:                 p.hue(hueError, 0.17),
+		colorkeys.Warning:               p.hue(hueWarning, 0.15),
+		colorkeys.Success:               p.hue(hueSuccess, 0.15),
+		colorkeys.Info:                  p.hue(hueInfo, 0.13),
+	}
+
+	out := make(map[string]string, len(colors))
+	for key, c := range colors {
+		out[key] = c.Hex()
+	}
+	return out
+}
+
+// terminal returns the ANSI palette: neutrals on the background to
+// foreground ramp and hues at a fixed chroma, the bright variants moved
+// further from the background.
+func (p palette) terminal() *types.TerminalPalette {
+	dark, light := p.bg, p.fg
+	if p.light {
+		dark, light = p.fg, p.bg
+	}
+	hue := func(h float64, bright bool) string {
+		c := p.hue(h, 0.14)
+		if bright {
+			l, ch, hh := c.OKLCH()
+			dl := 0.08
+			if p.light {
+				dl = -dl
+			}
+			c = color.FromOKLCH(l+dl, ch, hh, 1)
+		}
+		return c.Hex()
+	}
+	return &types.TerminalPalette{
+		Black:         color.Mix(dark, light, 0.15).Hex(),
+		Red:           hue(hueError, false),
+		Green:         hue(hueSuccess, false),
+		Yellow:        hue(hueWarning+15, false),
+		Blue:          hue(hueInfo, false),
+		Magenta:       hue(hueMagenta, false),
+		Cyan:          hue(hueCyan, false),
+		White:         color.Mix(dark, light, 0.8).Hex(),
+		BrightBlack:   color.Mix(dark, light, 0.45).Hex(),
+		BrightRed:     hue(hueError, true),
+		BrightGreen:   hue(hueSuccess, true),
+		BrightYellow:  hue(hueWarning+15, true),
+		BrightBlue:    hue(hueInfo, true),
+		BrightMagenta: hue(hueMagenta, true),
+		BrightCyan:    hue(hueCyan, true),
+		BrightWhite:   light.Hex(),
+		Background:    p.bg.Hex(),
+		Foreground:    p.fg.Hex(),
+		Cursor:        p.accent.Hex(),
+		Selection:     color.Mix(p.surface(0.08), p.accent, 0.25).Hex(),
+	}
+}
+
+func (p palette) tokenColors() []types.TokenColor {
+	_, accentChroma, accentHue := p.accent.OKLCH()
+	chroma := math.Max(accentChroma, 0.1)
+
+	tokens := []types.TokenColor{{
+		Name:  "Comment",
+		Scope: []string{"comment", "punctuation.definition.comment"},
+		Settings: map[string]string{
+			"foreground": color.Mix(p.fg, p.bg, 0.45).Hex(),
+			"fontStyle":  "italic",
+		},
+	}}
+	for _, role := range tokenRoles {
+		settings := map[string]string{"foreground": p.hue(accentHue+role.hue, chroma).Hex()}
+		if role.fontStyle != "" {
+			settings["fontStyle"] = role.fontStyle
+		}
+		tokens = append(tokens, types.TokenColor{
+			Name:     role.name,
+			Scope:    append([]string(nil), role.scopes...),
+			Settings: settings,
+		})
+	}
+	tokens = append(tokens, types.TokenColor{
+		Name:     "Invalid",
+		Scope:    []string{"invalid"},
+		Settings: map[string]string{"foreground": p.hue(hueError, 0.17).Hex()},
+	})
+	return tokens
+}
@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// alacrittyColorMap maps Alacritty color settings to TerminalPalette
+// fields.
+var alacrittyColorMap = func() map[string]string {
+	m := map[string]string{
+		"colors.primary.background":   "background",
+		"colors.primary.foreground":   "foreground",
+		"colors.cursor.cursor":        "cursor",
+		"colors.selection.background": "selection",
+	}
+	for i, name := range types.TerminalColorNames[:8] {
+		m["colors.normal."+name] = name
+		m["colors.bright."+name] = types.TerminalColorNames[i+8]
+	}
+	return m
+}()
+
+var (
+	// alacrittyTOMLPattern matches a [colors...] table header.
+	alacrittyTOMLPattern = regexp.MustCompile(`(?m)^\s*\[colors(\.[a-z_]+)*\]\s*(#.*)?$`)
+	// alacrittyYAMLPattern matches a top-level colors: mapping.
+	alacrittyYAMLPattern = regexp.MustCompile(`(?m)^colors:\s*(&\S+\s*)?(#.*)?$`)
+)
+
+// ImportAlacritty parses the colors of an Alacritty config, in the TOML
+// format of Alacritty 0.13 and later or the older YAML one, into a
+// ThemeDef. Settings Alacritty resolves at run time, such as a cursor
+// color of "CellForeground", are left for the service to derive.
+func ImportAlacritty(data []byte) (*types.ThemeDef, error) {
+	var settings map[string]string
+	var err error
+	if isAlacrittyYAML(data) {
+		settings, err = readYAML(data)
+	} else {
+		settings, err = readTOML(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid Alacritty config: %w", err)
+	}
+
+	palette := &types.TerminalPalette{}
+	for key, field := range alacrittyColorMap {
+		value, ok := settings[key]
+		if !ok || value == "CellForeground" || value == "CellBackground" {
+			continue
+		}
+		*palette.Field(field) = value
+	}
+	theme, err := terminalTheme(namelessFormats[FormatAlacritty], "alacritty", palette)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Alacritty colors: %w", err)
+	}
+	return theme, nil
+}
+
+// isAlacrittyTOML reports whether data looks like an Alacritty TOML
+// config with colors.
+func isAlacrittyTOML(data []byte) bool {
+	return alacrittyTOMLPattern.Match(data)
+}
+
+// isAlacrittyYAML reports whether data looks like an Alacritty YAML
+// config with colors.
+func isAlacrittyYAML(data []byte) bool {
+	return alacrittyYAMLPattern.Match(data)
+}
@@ -0,0 +1,279 @@
+package importer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf16"
+)
+
+// Binary plist ("bplist00") layout: the header, the objects, a table of
+// object offsets and a 32-byte trailer describing the table.
+
+const bplistTrailerSize = 32
+
+// bplistMaxVisits bounds the number of objects decoded, since shared
+// references let a small file describe an exponentially large tree.
+const bplistMaxVisits = 1 << 20
+
+// bplistEpoch is the reference date of binary plist dates.
+var bplistEpoch = time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// bplist decodes the objects of a binary plist.
+type bplist struct {
+	data    []byte
+	offsets []uint64
+	refSize int
+	// decoding marks objects on the current path, to reject cycles.
+	decoding []bool
+	visits   int
+}
+
+// decodeBinaryPlist decodes a binary plist and returns its root value.
+func decodeBinaryPlist(data []byte) (plistValue, error) {
+	if len(data) < len(bplistMagic)+bplistTrailerSize {
+		return plistValue{}, errors.New("binary plist is truncated")
+	}
+	trailer := data[len(data)-bplistTrailerSize:]
+	offsetSize := int(trailer[6])
+	refSize := int(trailer[7])
+	numObjects := binary.BigEndian.Uint64(trailer[8:])
+	topObject := binary.BigEndian.Uint64(trailer[16:])
+	tableOffset := binary.BigEndian.Uint64(trailer[24:])
+
+	objectsEnd := uint64(len(data) - bplistTrailerSize)
+	if !validIntSize(offsetSize) || !validIntSize(refSize) {
+		return plistValue{}, errors.New("binary plist has invalid integer sizes")
+	}
+	if numObjects == 0 || topObject >= numObjects {
+		return plistValue{}, errors.New("binary plist has no root object")
+	}
+	if tableOffset < uint64(len(bplistMagic)) || tableOffset > objectsEnd ||
+		numObjects > (objectsEnd-tableOffset)/uint64(offsetSize) {
+		return plistValue{}, errors.New("binary plist offset table is out of range")
+	}
+
+	p := &bplist{
+		data:     data[:objectsEnd],
+		offsets:  make([]uint64, numObjects),
+		refSize:  refSize,
+		decoding: make([]bool, numObjects),
+	}
+	for i := range p.offsets {
+		start := tableOffset + uint64(i*offsetSize)
+		p.offsets[i] = readUint(data[start : start+uint64(offsetSize)])
+		if p.offsets[i] < uint64(len(bplistMagic)) || p.offsets[i] >= tableOffset {
+			return plistValue{}, fmt.Errorf("binary plist object %d is out of range", i)
+		}
+	}
+	return p.object(topObject)
+}
+
+func validIntSize(n int) bool {
+	return n == 1 || n == 2 || n == 4 || n == 8
+}
+
+// readUint reads a big-endian unsigned integer of 1 to 8 bytes.
+func readUint(b []byte) uint64 {
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n
+}
+
+// bytes returns n bytes at off, or an error if they run past the objects.
+func (p *bplist) bytes(off, n uint64) ([]byte, error) {
+	if off > uint64(len(p.data)) || n > uint64(len(p.data))-off {
+		return nil, errors.New("binary plist object runs past the end of the data")
+	}
+	return p.data[off : off+n], nil
+}
+
+// object decodes the object with the given index.
+func (p *bplist) object(ref uint64) (plistValue, error) {
+	if ref >= uint64(len(p.offsets)) {
+		return plistValue{}, fmt.Errorf("binary plist reference %d is out of range", ref)
+	}
+	if p.decoding[ref] {
+		return plistValue{}, errors.New("binary plist contains a reference cycle")
+	}
+	if p.visits++; p.visits > bplistMaxVisits {
+		return plistValue{}, errors.New("binary plist is too large")
+	}
+	p.decoding[ref] = true
+	defer func() { p.decoding[ref] = false }()
+
+	off := p.offsets[ref]
+	marker := p.data[off]
+	kind, info := marker>>4, marker&0x0F
+	off++
+
+	switch kind {
+	case 0x0:
+		switch info {
+		case 0x8:
+			return plistValue{Kind: plistBool, Bool: false}, nil
+		case 0x9:
+			return plistValue{Kind: plistBool, Bool: true}, nil
+		}
+		return plistValue{}, fmt.Errorf("unsupported binary plist marker 0x%02x", marker)
+	case 0x1:
+		b, err := p.bytes(off, 1<<info)
+		if err != nil {
+			return plistValue{}, err
+		}
+		return plistValue{Kind: plistInteger, Integer: bplistInt(b)}, nil
+	case 0x2:
+		b, err := p.bytes(off, 1<<info)
+		if err != nil {
+			return plistValue{}, err
+		}
+		switch len(b) {
+		case 4:
+			return plistValue{Kind: plistReal, Real: float64(math.Float32frombits(binary.BigEndian.Uint32(b)))}, nil
+		case 8:
+			return plistValue{Kind: plistReal, Real: math.Float64frombits(binary.BigEndian.Uint64(b))}, nil
+		}
+		return plistValue{}, fmt.Errorf("unsupported binary plist real size %d", len(b))
+	case 0x3:
+		b, err := p.bytes(off, 8)
+		if err != nil || info != 0x3 {
+			return plistValue{}, errors.New("invalid binary plist date")
+		}
+		secs := math.Float64frombits(binary.BigEndian.Uint64(b))
+		date := bplistEpoch.Add(time.Duration(secs * float64(time.Second)))
+		return plistValue{Kind: plistDate, Date: date}, nil
+	case 0x4, 0x5, 0x6:
+		n, start, err := p.count(off, info)
+		if err != nil {
+			return plistValue{}, err
+		}
+		size := n
+		if kind == 0x6 {
+			size = 2 * n
+		}
+		b, err := p.bytes(start, size)
+		if err != nil {
+			return plistValue{}, err
+		}
+		switch kind {
+		case 0x4:
+			return plistValue{Kind: plistData, Data: append([]byte(nil), b...)}, nil
+		case 0x5:
+			return plistValue{Kind: plistString, String: string(b)}, nil
+		default:
+			units := make([]uint16, n)
+			for i := range units {
+				units[i] = binary.BigEndian.Uint16(b[2*i:])
+			}
+			return plistValue{Kind: plistString, String: string(utf16.Decode(units))}, nil
+		}
+	case 0x8:
+		b, err := p.bytes(off, uint64(info)+1)
+		if err != nil {
+			return plistValue{}, err
+		}
+		return plistValue{Kind: plistUID, Integer: int64(readUint(b))}, nil
+	case 0xA, 0xC:
+		n, start, err := p.count(off, info)
+		if err != nil {
+			return plistValue{}, err
+		}
+		refs, err := p.refs(start, n)
+		if err != nil {
+			return plistValue{}, err
+		}
+		arr := make([]plistValue, 0, len(refs))
+		for _, r := range refs {
+			v, err := p.object(r)
+			if err != nil {
+				return plistValue{}, err
+			}
+			arr = append(arr, v)
+		}
+		return plistValue{Kind: plistArray, Array: arr}, nil
+	case 0xD:
+		n, start, err := p.count(off, info)
+		if err != nil {
+			return plistValue{}, err
+		}
+		refs, err := p.refs(start, 2*n)
+		if err != nil {
+			return plistValue{}, err
+		}
+		d := &plistDict{Items: make([]plistItem, 0, n)}
+		for i := uint64(0); i < n; i++ {
+			k, err := p.object(refs[i])
+			if err != nil {
+				return plistValue{}, err
+			}
+			if k.Kind != plistString {
+				return plistValue{}, errors.New("binary plist dict key is not a string")
+			}
+			v, err := p.object(refs[n+i])
+			if err != nil {
+				return plistValue{}, err
+			}
+			d.Items = append(d.Items, plistItem{Key: k.String, Value: v})
+		}
+		return plistValue{Kind: plistDictKind, Dict: d}, nil
+	}
+	return plistValue{}, fmt.Errorf("unsupported binary plist marker 0x%02x", marker)
+}
+
+// count returns the element count encoded in a marker's low nibble, or
+// in the integer object that follows when the nibble is 0xF, along with
+// the offset of the object's contents.
+func (p *bplist) count(off uint64, info byte) (uint64, uint64, error) {
+	if info != 0xF {
+		return uint64(info), off, nil
+	}
+	b, err := p.bytes(off, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	if b[0]>>4 != 0x1 || b[0]&0x0F > 3 {
+		return 0, 0, errors.New("invalid binary plist length")
+	}
+	size := uint64(1) << (b[0] & 0x0F)
+	nb, err := p.bytes(off+1, size)
+	if err != nil {
+		return 0, 0, err
+	}
+	n := readUint(nb)
+	if n > uint64(len(p.data)) {
+		return 0, 0, errors.New("binary plist length is out of range")
+	}
+	return n, off + 1 + size, nil
+}
+
+// refs reads n object references starting at off.
+func (p *bplist) refs(off, n uint64) ([]uint64, error) {
+	size := uint64(p.refSize)
+	if n > uint64(len(p.data))/size {
+		return nil, errors.New("binary plist container is out of range")
+	}
+	b, err := p.bytes(off, n*size)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]uint64, n)
+	for i := range refs {
+		refs[i] = readUint(b[uint64(i)*size : uint64(i+1)*size])
+	}
+	return refs, nil
+}
+
+// bplistInt decodes a binary plist integer. Integers of 1, 2 and 4 bytes
+// are unsigned; 8-byte integers are signed. Of 16-byte integers only the
+// low 8 bytes are kept.
+func bplistInt(b []byte) int64 {
+	if len(b) > 8 {
+		b = b[len(b)-8:]
+	}
+	n := readUint(b)
+	return int64(n)
+}
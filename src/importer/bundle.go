@@ -0,0 +1,283 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+var (
+	// ErrIncludeCycle is returned when VS Code theme files include each
+	// other in a loop.
+	ErrIncludeCycle = errors.New("include cycle")
+	// ErrIncludeUnresolved is returned when a single VS Code theme file
+	// that includes another file is imported without its bundle.
+	ErrIncludeUnresolved = errors.New("theme includes another file")
+	// ErrIncludeMissing is returned when an included file is not part of
+	// the bundle.
+	ErrIncludeMissing = errors.New("included file not found")
+	// ErrBundleEntry is returned when the theme file to import cannot be
+	// determined or does not exist.
+	ErrBundleEntry = errors.New("cannot determine bundle entry theme")
+)
+
+// Archive limits guard against zip bombs.
+const (
+	maxBundleFiles    = 1000
+	maxBundleFileSize = 8 << 20
+	maxBundleSize     = 32 << 20
+)
+
+// Bundle is a set of theme files keyed by their slash-separated path
+// relative to the bundle root.
+type Bundle map[string][]byte
+
+// CleanPath returns name as a bundle key: slash-separated, without a
+// leading "./" or "/".
+func CleanPath(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// ReadZipBundle reads every file of a zip archive into a Bundle.
+func ReadZipBundle(data []byte) (Bundle, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+	if len(zr.File) > maxBundleFiles {
+		return nil, fmt.Errorf("archive has %d files, more than the limit of %d", len(zr.File), maxBundleFiles)
+	}
+
+	bundle := make(Bundle)
+	var total int64
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		content, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		total += int64(len(content))
+		if total > maxBundleSize {
+			return nil, fmt.Errorf("archive contents exceed %d bytes", maxBundleSize)
+		}
+		bundle[CleanPath(f.Name)] = content
+	}
+	return bundle, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, maxBundleFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", f.Name, err)
+	}
+	if len(content) > maxBundleFileSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", f.Name, maxBundleFileSize)
+	}
+	return content, nil
+}
+
+// ReadDirBundle reads the theme files below dir into a Bundle: files with
+// a .json, .jsonc or .tmTheme extension. Hidden directories and
+// node_modules are not descended into.
+func ReadDirBundle(dir string) (Bundle, error) {
+	bundle := make(Bundle)
+	var total int64
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			base := d.Name()
+			if name != dir && (strings.HasPrefix(base, ".") || base == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !isThemeFile(name) {
+			return nil
+		}
+		if len(bundle) >= maxBundleFiles {
+			return fmt.Errorf("%s has more than %d theme files", dir, maxBundleFiles)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxBundleFileSize {
+			return fmt.Errorf("%s is larger than %d bytes", name, maxBundleFileSize)
+		}
+		total += info.Size()
+		if total > maxBundleSize {
+			return fmt.Errorf("theme files in %s exceed %d bytes", dir, maxBundleSize)
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		bundle[CleanPath(filepath.ToSlash(rel))] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// ImportVSCodeBundle imports the VS Code theme at entry, following its
+// "include" references to other files of the bundle. Include paths are
+// relative to the including file. Colors and semantic token colors of an
+// included file are overridden by the including file, and its token color
+// rules come first, so the theme that includes a base wins.
+//
+// When entry is empty the bundle must contain exactly one JSON theme
+// that no other file includes.
+func ImportVSCodeBundle(bundle Bundle, entry string) (*types.ThemeDef, error) {
+	if entry == "" {
+		var err error
+		if entry, err = bundleEntry(bundle); err != nil {
+			return nil, err
+		}
+	}
+	entry = CleanPath(entry)
+	if _, ok := bundle[entry]; !ok {
+		return nil, fmt.Errorf("%w: %s is not in the bundle", ErrBundleEntry, entry)
+	}
+
+	merged, err := resolveInclude(bundle, entry, nil)
+	if err != nil {
+		return nil, err
+	}
+	merged.Include = ""
+	return vscodeToTheme(merged), nil
+}
+
+// resolveInclude decodes the file at name and merges in its include
+// chain. chain holds the files currently being resolved, for cycle
+// detection.
+func resolveInclude(bundle Bundle, name string, chain []string) (*vscodeThemeFile, error) {
+	for i, seen := range chain {
+		if seen == name {
+			loop := append(append([]string(nil), chain[i:]...), name)
+			return nil, fmt.Errorf("%w: %s", ErrIncludeCycle, strings.Join(loop, " -> "))
+		}
+	}
+	chain = append(chain, name)
+
+	file, err := decodeVSCode(bundle[name])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if file.Include == "" {
+		return file, nil
+	}
+
+	parentName := includePath(name, file.Include)
+	if _, ok := bundle[parentName]; !ok {
+		return nil, fmt.Errorf("%w: %s includes %q (%s)", ErrIncludeMissing, name, file.Include, parentName)
+	}
+	parent, err := resolveInclude(bundle, parentName, chain)
+	if err != nil {
+		return nil, err
+	}
+	return mergeVSCode(parent, file), nil
+}
+
+// includePath resolves an include reference relative to the file that
+// contains it.
+func includePath(from, include string) string {
+	return CleanPath(path.Join(path.Dir(from), strings.ReplaceAll(include, "\\", "/")))
+}
+
+// mergeVSCode overlays child onto parent.
+func mergeVSCode(parent, child *vscodeThemeFile) *vscodeThemeFile {
+	out := &vscodeThemeFile{
+		Name:                 child.Name,
+		Type:                 child.Type,
+		Colors:               make(map[string]string, len(parent.Colors)+len(child.Colors)),
+		SemanticHighlighting: parent.SemanticHighlighting,
+		SemanticTokenColors:  make(map[string]vscodeSemanticStyle),
+	}
+	if child.SemanticHighlighting != nil {
+		out.SemanticHighlighting = child.SemanticHighlighting
+	}
+	if out.Name == "" {
+		out.Name = parent.Name
+	}
+	if out.Type == "" {
+		out.Type = parent.Type
+	}
+	for _, f := range []*vscodeThemeFile{parent, child} {
+		for k, v := range f.Colors {
+			out.Colors[k] = v
+		}
+		for k, v := range f.SemanticTokenColors {
+			out.SemanticTokenColors[k] = v
+		}
+		out.TokenColors = append(out.TokenColors, f.TokenColors...)
+	}
+	return out
+}
+
+// bundleEntry picks the only JSON file of bundle that no other file
+// includes.
+func bundleEntry(bundle Bundle) (string, error) {
+	included := make(map[string]bool)
+	var candidates []string
+	for name, data := range bundle {
+		if !isThemeJSON(name) {
+			continue
+		}
+		candidates = append(candidates, name)
+		if file, err := decodeVSCode(data); err == nil && file.Include != "" {
+			included[includePath(name, file.Include)] = true
+		}
+	}
+
+	var roots []string
+	for _, name := range candidates {
+		if !included[name] {
+			roots = append(roots, name)
+		}
+	}
+	sort.Strings(roots)
+	switch len(roots) {
+	case 1:
+		return roots[0], nil
+	case 0:
+		return "", fmt.Errorf("%w: no JSON theme files", ErrBundleEntry)
+	default:
+		return "", fmt.Errorf("%w: several candidates (%s); specify one", ErrBundleEntry, strings.Join(roots, ", "))
+	}
+}
+
+func isThemeFile(name string) bool {
+	return isThemeJSON(name) || strings.HasSuffix(strings.ToLower(name), ".tmtheme")
+}
+
+func isThemeJSON(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, ".json") || strings.HasSuffix(lower, ".jsonc")
+}
@@ -4,33 +4,62 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"path"
+	"strings"
 
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
 	"github.com/orchestra-mcp/themes/src/types"
 )
 
 // Format constants for theme source detection.
 const (
-	FormatVSCodeJSON    = "vscode-json"
-	FormatTmTheme       = "tmtheme"
-	FormatOrchestraJSON = "orchestra-json"
+	FormatVSCodeJSON      = "vscode-json"
+	FormatTmTheme         = "tmtheme"
+	FormatOrchestraJSON   = "orchestra-json"
+	FormatITermColors     = "itermcolors"
+	FormatAlacritty       = "alacritty"
+	FormatKitty           = "kitty"
+	FormatWindowsTerminal = "windows-terminal"
+	FormatXresources      = "xresources"
+	FormatSublime         = "sublime-color-scheme"
 )
 
+// namelessFormats maps formats whose files may carry no theme name to the
+// placeholder name their importers use instead.
+var namelessFormats = map[string]string{
+	FormatITermColors:     "Imported iTerm2 Colors",
+	FormatAlacritty:       "Imported Alacritty Colors",
+	FormatKitty:           "Imported kitty Colors",
+	FormatWindowsTerminal: "Imported Windows Terminal Scheme",
+	FormatXresources:      "Imported Xresources Colors",
+	FormatSublime:         "Imported Sublime Color Scheme",
+}
+
 // DetectFormat inspects raw bytes and returns the detected theme format.
-// Returns one of: "vscode-json", "tmtheme", or "orchestra-json".
+// Returns one of: "vscode-json", "tmtheme", "itermcolors", "alacritty",
+// "kitty", "windows-terminal", "xresources", "sublime-color-scheme" or
+// "orchestra-json".
 func DetectFormat(data []byte) string {
-	trimmed := bytes.TrimSpace(data)
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM))
 
-	// XML plist files start with <?xml or <plist or <!DOCTYPE plist
-	if bytes.HasPrefix(trimmed, []byte("<?xml")) ||
+	// XML plist files start with <?xml or <plist or <!DOCTYPE plist;
+	// binary plists with their magic number.
+	if isBinaryPlist(data) ||
+		bytes.HasPrefix(trimmed, []byte("<?xml")) ||
 		bytes.HasPrefix(trimmed, []byte("<plist")) ||
 		bytes.HasPrefix(trimmed, []byte("<!DOCTYPE plist")) {
+		if root, err := decodePlistDict(data); err == nil && isITermColors(root) {
+			return FormatITermColors
+		}
 		return FormatTmTheme
 	}
 
-	// Try to parse as JSON and inspect fields.
+	// Try to parse as JSON and inspect fields. VS Code themes are often
+	// JSONC, so comments and trailing commas are tolerated.
 	if len(trimmed) > 0 && trimmed[0] == '{' {
 		var probe map[string]json.RawMessage
-		if err := json.Unmarshal(trimmed, &probe); err == nil {
+		if err := unmarshalJSONC(trimmed, &probe); err == nil {
 			// Orchestra themes always have "id"; check this first since
 			// both formats share "colors".
 			_, hasID := probe["id"]
@@ -43,7 +72,29 @@ func DetectFormat(data []byte) string {
 			if hasTokenColors {
 				return FormatVSCodeJSON
 			}
+			if isWindowsTerminal(probe) {
+				return FormatWindowsTerminal
+			}
+			if isSublimeScheme(probe) {
+				return FormatSublime
+			}
+		} else if bytes.Contains(trimmed, []byte(`"tokenColors"`)) {
+			// A malformed VS Code theme: let its importer report where.
+			return FormatVSCodeJSON
+		} else if bytes.Contains(trimmed, []byte(`"globals"`)) || bytes.Contains(trimmed, []byte(`"rules"`)) {
+			return FormatSublime
 		}
+		return FormatOrchestraJSON
+	}
+
+	// Terminal emulator configs are plain text.
+	switch {
+	case isAlacrittyTOML(trimmed), isAlacrittyYAML(trimmed):
+		return FormatAlacritty
+	case isXresources(trimmed):
+		return FormatXresources
+	case isKitty(trimmed):
+		return FormatKitty
 	}
 
 	return FormatOrchestraJSON
@@ -51,13 +102,46 @@ func DetectFormat(data []byte) string {
 
 // Import auto-detects the theme format and parses accordingly.
 func Import(data []byte) (*types.ThemeDef, error) {
+	return importAs(DetectFormat(data), data)
+}
+
+// ImportFile imports data like Import. When the file stores no theme
+// name, the name and ID are taken from filename without its extension.
+func ImportFile(filename string, data []byte) (*types.ThemeDef, error) {
 	format := DetectFormat(data)
+	theme, err := importAs(format, data)
+	if err != nil {
+		return nil, err
+	}
+	if placeholder, ok := namelessFormats[format]; ok && theme.Name == placeholder {
+		base := path.Base(strings.ReplaceAll(filename, "\\", "/"))
+		if stem := strings.TrimSuffix(base, path.Ext(base)); slugify(stem) != "" {
+			theme.Name = stem
+			theme.ID = slugify(stem)
+		}
+	}
+	return theme, nil
+}
 
+// importAs parses data in the given format.
+func importAs(format string, data []byte) (*types.ThemeDef, error) {
 	switch format {
 	case FormatVSCodeJSON:
 		return ImportVSCode(data)
 	case FormatTmTheme:
 		return ImportTmTheme(data)
+	case FormatITermColors:
+		return ImportITermColors(data)
+	case FormatAlacritty:
+		return ImportAlacritty(data)
+	case FormatKitty:
+		return ImportKitty(data)
+	case FormatWindowsTerminal:
+		return ImportWindowsTerminal(data)
+	case FormatXresources:
+		return ImportXresources(data)
+	case FormatSublime:
+		return ImportSublime(data)
 	case FormatOrchestraJSON:
 		return importOrchestra(data)
 	default:
@@ -65,17 +149,66 @@ func Import(data []byte) (*types.ThemeDef, error) {
 	}
 }
 
+// Parse decodes data in any supported format like Import, but does not
+// reject Orchestra themes without an ID, so that callers can report every
+// problem through the validator instead.
+func Parse(data []byte) (*types.ThemeDef, error) {
+	if DetectFormat(data) == FormatOrchestraJSON {
+		return decodeOrchestra(data)
+	}
+	return Import(data)
+}
+
 // importOrchestra parses an Orchestra-native JSON theme.
 func importOrchestra(data []byte) (*types.ThemeDef, error) {
-	var theme types.ThemeDef
-	if err := json.Unmarshal(data, &theme); err != nil {
-		return nil, fmt.Errorf("invalid Orchestra theme JSON: %w", err)
+	theme, err := decodeOrchestra(data)
+	if err != nil {
+		return nil, err
 	}
 	if theme.ID == "" {
 		return nil, fmt.Errorf("theme ID is required")
 	}
+	return theme, nil
+}
+
+// decodeOrchestra unmarshals an Orchestra-native JSON theme, rewrites
+// legacy color key aliases to their canonical names and fills in parsed
+// fields of semantic token rules given only as selectors.
+func decodeOrchestra(data []byte) (*types.ThemeDef, error) {
+	var theme types.ThemeDef
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return nil, fmt.Errorf("invalid Orchestra theme JSON: %w", err)
+	}
 	if theme.Source == "" {
 		theme.Source = "orchestra"
 	}
+	theme.Colors = colorkeys.Normalize(theme.Colors)
+	FillSemanticSelectors(theme.SemanticTokenColors)
 	return &theme, nil
 }
+
+// normalizeColor returns value in canonical color form, or false when it
+// is not a valid color.
+func normalizeColor(value string) (string, bool) {
+	normalized, err := color.Normalize(value)
+	if err != nil {
+		return "", false
+	}
+	return normalized, true
+}
+
+// normalizeTokenSettings normalizes the color entries of a token rule's
+// settings in place, dropping values that are not valid colors.
+func normalizeTokenSettings(settings map[string]string) {
+	for _, key := range []string{"foreground", "background"} {
+		value, ok := settings[key]
+		if !ok {
+			continue
+		}
+		if normalized, ok := normalizeColor(value); ok {
+			settings[key] = normalized
+		} else {
+			delete(settings, key)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// iTerm2 color names mapped to canonical Orchestra keys. The ANSI colors
+// ("Ansi 0 Color" to "Ansi 15 Color") and every other color are kept
+// under the "raw." prefix.
+var itermColorMap = map[string][]string{
+	"Background Color": {colorkeys.Background, colorkeys.EditorBackground},
+	"Foreground Color": {colorkeys.Foreground, colorkeys.EditorForeground},
+	"Cursor Color":     {colorkeys.EditorCursor},
+	"Selection Color":  {colorkeys.EditorSelection},
+}
+
+// ImportITermColors parses an iTerm2 .itermcolors plist, in XML or
+// binary form, into a ThemeDef whose Terminal holds the ANSI palette.
+// The file has no name, so a placeholder is used; ImportFile names the
+// theme after the file instead.
+func ImportITermColors(data []byte) (*types.ThemeDef, error) {
+	root, err := decodePlistDict(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid itermcolors plist: %w", err)
+	}
+
+	theme := &types.ThemeDef{
+		Name:   namelessFormats[FormatITermColors],
+		Source: "itermcolors",
+		Colors: make(map[string]string),
+	}
+	theme.ID = slugify(theme.Name)
+
+	for _, item := range root.Items {
+		if item.Value.Dict == nil || !strings.HasSuffix(item.Key, " Color") {
+			continue
+		}
+		c, err := itermColor(item.Value.Dict)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", item.Key, err)
+		}
+		hex := c.Hex()
+		for _, mapped := range itermColorMap[item.Key] {
+			theme.Colors[mapped] = hex
+		}
+		theme.Colors[colorkeys.RawPrefix+item.Key] = hex
+	}
+	if _, ok := theme.Colors[colorkeys.RawPrefix+"Ansi 0 Color"]; !ok {
+		return nil, errors.New("itermcolors file has no ANSI colors")
+	}
+	theme.Terminal = TerminalFromRaw(theme.Source, theme.Colors)
+	theme.Type = detectThemeType(theme.Colors)
+	return theme, nil
+}
+
+// ITermColorMap returns a copy of the mapping from iTerm2 color names to
+// canonical keys used by ImportITermColors.
+func ITermColorMap() map[string][]string {
+	out := make(map[string][]string, len(itermColorMap))
+	for key, mapped := range itermColorMap {
+		out[key] = append([]string(nil), mapped...)
+	}
+	return out
+}
+
+// itermColor converts a color component dict to sRGB. Components are
+// reals in [0, 1] interpreted in the dict's "Color Space": sRGB (and
+// device RGB, its usual stand-in), Display P3, or calibrated (generic,
+// gamma 1.8) RGB, which files without a color space use.
+func itermColor(d *plistDict) (color.Color, error) {
+	var ch [4]float64
+	for i, name := range []string{"Red", "Green", "Blue", "Alpha"} {
+		v, ok := dictValue(d, name+" Component")
+		if !ok {
+			if name == "Alpha" {
+				ch[i] = 1
+				continue
+			}
+			return color.Color{}, fmt.Errorf("missing %s component", strings.ToLower(name))
+		}
+		f, ok := v.Float()
+		if !ok || math.IsNaN(f) || math.IsInf(f, 0) {
+			return color.Color{}, fmt.Errorf("%s component is not a finite number", strings.ToLower(name))
+		}
+		ch[i] = f
+	}
+
+	switch space := dictGet(d, "Color Space"); strings.ToLower(space) {
+	case "srgb", "device":
+		return color.Color{R: ch[0], G: ch[1], B: ch[2], A: ch[3]}, nil
+	case "p3":
+		return color.FromDisplayP3(ch[0], ch[1], ch[2], ch[3]), nil
+	case "", "calibrated":
+		return color.FromGamma(ch[0], ch[1], ch[2], ch[3], 1.8), nil
+	default:
+		return color.Color{}, fmt.Errorf("unsupported color space %q", space)
+	}
+}
+
+// isITermColors reports whether a plist dict looks like an iTerm2
+// color preset.
+func isITermColors(d *plistDict) bool {
+	v, ok := dictValue(d, "Ansi 0 Color")
+	return ok && v.Dict != nil
+}
@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// utf8BOM is the UTF-8 byte order mark some editors write before JSON.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// SyntaxError is a JSON or JSONC decoding error with the position it
+// occurred at. Line and Column are 1-based; Column counts bytes.
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// stripJSONC turns JSONC (JSON with // and /* */ comments and trailing
+// commas, as used by VS Code) into plain JSON. Comments, trailing commas
+// and a leading byte order mark are overwritten with spaces rather than
+// removed, so byte offsets, lines and columns in the result match the
+// input.
+func stripJSONC(data []byte) ([]byte, error) {
+	out := bytes.Clone(data)
+	if bytes.HasPrefix(out, utf8BOM) {
+		copy(out, "   ")
+	}
+
+	blank := func(from, to int) {
+		for i := from; i < to; i++ {
+			if out[i] != '\n' && out[i] != '\r' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	lastComma := -1
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+		case c == '"':
+			lastComma = -1
+			for i++; i < len(out) && out[i] != '"'; i++ {
+				if out[i] == '\\' {
+					i++
+				}
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			end := bytes.IndexByte(out[i:], '\n')
+			if end < 0 {
+				end = len(out) - i
+			}
+			blank(i, i+end)
+			i += end - 1
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			end := bytes.Index(out[i+2:], []byte("*/"))
+			if end < 0 {
+				line, col := position(data, i)
+				return nil, &SyntaxError{Line: line, Column: col, Msg: "unterminated block comment"}
+			}
+			blank(i, i+2+end+2)
+			i += 2 + end + 1
+		case c == ',':
+			lastComma = i
+		case c == '}' || c == ']':
+			if lastComma >= 0 {
+				out[lastComma] = ' '
+			}
+			lastComma = -1
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		default:
+			lastComma = -1
+		}
+	}
+	return out, nil
+}
+
+// unmarshalJSONC decodes JSONC data into v. Syntax and type errors are
+// returned as *SyntaxError.
+func unmarshalJSONC(data []byte, v any) error {
+	clean, err := stripJSONC(data)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(clean, v)
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return syntaxErrorAt(data, syntaxErr.Offset, syntaxErr.Error())
+	case errors.As(err, &typeErr):
+		return syntaxErrorAt(data, typeErr.Offset, typeErr.Error())
+	}
+	return err
+}
+
+// syntaxErrorAt builds a SyntaxError for the byte after offset, which is
+// where encoding/json reports errors.
+func syntaxErrorAt(data []byte, offset int64, msg string) *SyntaxError {
+	i := int(offset) - 1
+	if i < 0 {
+		i = 0
+	}
+	line, col := position(data, i)
+	return &SyntaxError{Line: line, Column: col, Msg: msg}
+}
+
+// position returns the 1-based line and column of byte offset i.
+func position(data []byte, i int) (line, col int) {
+	if i > len(data) {
+		i = len(data)
+	}
+	line = 1 + bytes.Count(data[:i], []byte("\n"))
+	col = i - bytes.LastIndexByte(data[:i], '\n')
+	return line, col
+}
@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// kittyColorMap maps kitty color options, other than color0 to color15,
+// to TerminalPalette fields.
+var kittyColorMap = map[string]string{
+	"background":           "background",
+	"foreground":           "foreground",
+	"cursor":               "cursor",
+	"selection_background": "selection",
+}
+
+// kittyPattern matches a kitty color option line.
+var kittyPattern = regexp.MustCompile(`(?m)^\s*(color[0-9]{1,3}|foreground|background|selection_background)[ \t]+[^\s:=]`)
+
+// kittyNamePattern matches the "## name:" metadata line of kitty themes.
+var kittyNamePattern = regexp.MustCompile(`^##\s*name:\s*(.+)$`)
+
+// ImportKitty parses a kitty .conf color scheme into a ThemeDef. The
+// theme is named from the "## name:" comment kitty-themes files start
+// with, if present. Options other than colors, and "include" lines, are
+// ignored.
+func ImportKitty(data []byte) (*types.ThemeDef, error) {
+	palette := &types.TerminalPalette{}
+	name := namelessFormats[FormatKitty]
+
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if m := kittyNamePattern.FindStringSubmatch(line); m != nil {
+			name = strings.TrimSpace(m[1])
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		option, value := fields[0], fields[1]
+		if field, ok := kittyColorMap[option]; ok {
+			if value != "none" {
+				*palette.Field(field) = value
+			}
+			continue
+		}
+		if n, ok := strings.CutPrefix(option, "color"); ok {
+			if i, err := strconv.Atoi(n); err == nil && i >= 0 && i < 16 {
+				*palette.Field(types.TerminalColorNames[i]) = value
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid kitty config: %w", err)
+	}
+
+	theme, err := terminalTheme(name, "kitty", palette)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kitty colors: %w", err)
+	}
+	return theme, nil
+}
+
+// isKitty reports whether data looks like a kitty color scheme.
+func isKitty(data []byte) bool {
+	return kittyPattern.Match(data)
+}
@@ -1,14 +1,37 @@
 package importer
 
-import "encoding/xml"
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
 
-// tmTheme plist XML structures.
+// Property list decoding, for tmTheme files and other Apple formats.
+// Both the XML and the binary (bplist00) encodings are supported.
 
-type plistRoot struct {
-	XMLName xml.Name  `xml:"plist"`
-	Dict    plistDict `xml:"dict"`
-}
+// plistKind is the type of a plistValue.
+type plistKind int
+
+const (
+	plistString plistKind = iota
+	plistInteger
+	plistReal
+	plistBool
+	plistData
+	plistDate
+	plistArray
+	plistDictKind
+	// plistUID only occurs in binary plists written by NSKeyedArchiver.
+	plistUID
+)
 
+// plistDict is a dictionary whose entries keep their file order.
 type plistDict struct {
 	Items []plistItem
 }
@@ -18,130 +41,274 @@ type plistItem struct {
 	Value plistValue
 }
 
+// plistValue is a decoded plist value. Kind selects the field in use;
+// Array holds arrays of any value type.
 type plistValue struct {
-	String string
-	Array  []plistDict
-	Dict   *plistDict
+	Kind    plistKind
+	String  string
+	Integer int64
+	Real    float64
+	Bool    bool
+	Data    []byte
+	Date    time.Time
+	Array   []plistValue
+	Dict    *plistDict
+}
+
+// Float returns an integer or real value as a float64.
+func (v plistValue) Float() (float64, bool) {
+	switch v.Kind {
+	case plistReal:
+		return v.Real, true
+	case plistInteger:
+		return float64(v.Integer), true
+	}
+	return 0, false
+}
+
+// bplistMagic starts every binary plist.
+var bplistMagic = []byte("bplist00")
+
+// isBinaryPlist reports whether data is a binary plist.
+func isBinaryPlist(data []byte) bool {
+	return bytes.HasPrefix(data, bplistMagic)
+}
+
+// DecodePlist decodes an XML or binary plist into Go values: string,
+// int64, float64, bool, []byte, time.Time, []any and map[string]any.
+// Binary plist UIDs decode as uint64.
+func DecodePlist(data []byte) (any, error) {
+	v, err := decodePlist(data)
+	if err != nil {
+		return nil, err
+	}
+	return v.native(), nil
+}
+
+// native converts v to the Go values documented on DecodePlist.
+func (v plistValue) native() any {
+	switch v.Kind {
+	case plistInteger:
+		return v.Integer
+	case plistReal:
+		return v.Real
+	case plistBool:
+		return v.Bool
+	case plistData:
+		return v.Data
+	case plistDate:
+		return v.Date
+	case plistUID:
+		return uint64(v.Integer)
+	case plistArray:
+		arr := make([]any, len(v.Array))
+		for i, elem := range v.Array {
+			arr[i] = elem.native()
+		}
+		return arr
+	case plistDictKind:
+		m := make(map[string]any, len(v.Dict.Items))
+		for _, item := range v.Dict.Items {
+			m[item.Key] = item.Value.native()
+		}
+		return m
+	default:
+		return v.String
+	}
 }
 
-// UnmarshalXML parses plist <dict> elements into key-value pairs.
-func (d *plistDict) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
-	d.Items = nil
-	var currentKey string
-	expectKey := true
+// decodePlist decodes an XML or binary plist and returns its root value.
+func decodePlist(data []byte) (plistValue, error) {
+	if isBinaryPlist(data) {
+		return decodeBinaryPlist(data)
+	}
+	return decodeXMLPlist(data)
+}
 
+// decodePlistDict decodes a plist whose root value must be a dictionary.
+func decodePlistDict(data []byte) (*plistDict, error) {
+	root, err := decodePlist(data)
+	if err != nil {
+		return nil, err
+	}
+	if root.Kind != plistDictKind {
+		return nil, errors.New("plist root is not a dict")
+	}
+	return root.Dict, nil
+}
+
+// decodeXMLPlist decodes the XML encoding. The value may be wrapped in a
+// <plist> element or stand alone.
+func decodeXMLPlist(data []byte) (plistValue, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
 	for {
 		tok, err := dec.Token()
+		if err == io.EOF {
+			return plistValue{}, errors.New("no plist value found")
+		}
+		if err != nil {
+			return plistValue{}, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local == "plist" {
+			continue
+		}
+		return decodeXMLValue(dec, start)
+	}
+}
+
+// decodeXMLValue decodes the element opened by start.
+func decodeXMLValue(dec *xml.Decoder, start xml.StartElement) (plistValue, error) {
+	switch start.Name.Local {
+	case "dict":
+		d, err := decodeXMLDict(dec)
+		if err != nil {
+			return plistValue{}, err
+		}
+		return plistValue{Kind: plistDictKind, Dict: d}, nil
+	case "array":
+		arr, err := decodeXMLArray(dec)
+		if err != nil {
+			return plistValue{}, err
+		}
+		return plistValue{Kind: plistArray, Array: arr}, nil
+	case "true", "false":
+		if err := dec.Skip(); err != nil {
+			return plistValue{}, err
+		}
+		return plistValue{Kind: plistBool, Bool: start.Name.Local == "true"}, nil
+	}
+
+	var text string
+	if err := dec.DecodeElement(&text, &start); err != nil {
+		return plistValue{}, err
+	}
+	switch start.Name.Local {
+	case "string":
+		return plistValue{Kind: plistString, String: text}, nil
+	case "integer":
+		n, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+		if err != nil {
+			return plistValue{}, fmt.Errorf("invalid plist integer %q", text)
+		}
+		return plistValue{Kind: plistInteger, Integer: n}, nil
+	case "real":
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return plistValue{}, fmt.Errorf("invalid plist real %q", text)
+		}
+		return plistValue{Kind: plistReal, Real: f}, nil
+	case "data":
+		compact := strings.Join(strings.Fields(text), "")
+		b, err := base64.StdEncoding.DecodeString(compact)
+		if err != nil {
+			return plistValue{}, fmt.Errorf("invalid plist data: %w", err)
+		}
+		return plistValue{Kind: plistData, Data: b}, nil
+	case "date":
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
 		if err != nil {
-			return err
+			return plistValue{}, fmt.Errorf("invalid plist date %q", text)
 		}
+		return plistValue{Kind: plistDate, Date: t}, nil
+	default:
+		return plistValue{}, fmt.Errorf("unknown plist element <%s>", start.Name.Local)
+	}
+}
 
+// decodeXMLDict decodes the <key>/value pairs of a <dict> up to its end
+// element.
+func decodeXMLDict(dec *xml.Decoder) (*plistDict, error) {
+	d := &plistDict{}
+	var key string
+	haveKey := false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
 		switch t := tok.(type) {
 		case xml.StartElement:
-			switch t.Name.Local {
-			case "key":
-				var s string
-				if err := dec.DecodeElement(&s, &t); err != nil {
-					return err
-				}
-				currentKey = s
-				expectKey = false
-			case "string":
-				var s string
-				if err := dec.DecodeElement(&s, &t); err != nil {
-					return err
-				}
-				d.Items = append(d.Items, plistItem{
-					Key:   currentKey,
-					Value: plistValue{String: s},
-				})
-				expectKey = true
-			case "dict":
-				var child plistDict
-				if err := dec.DecodeElement(&child, &t); err != nil {
-					return err
-				}
-				d.Items = append(d.Items, plistItem{
-					Key:   currentKey,
-					Value: plistValue{Dict: &child},
-				})
-				expectKey = true
-			case "array":
-				arr, err := decodeArray(dec)
-				if err != nil {
-					return err
-				}
-				d.Items = append(d.Items, plistItem{
-					Key:   currentKey,
-					Value: plistValue{Array: arr},
-				})
-				expectKey = true
-			default:
-				if err := dec.Skip(); err != nil {
-					return err
+			if t.Name.Local == "key" {
+				if err := dec.DecodeElement(&key, &t); err != nil {
+					return nil, err
 				}
-				_ = expectKey
+				haveKey = true
+				continue
+			}
+			if !haveKey {
+				return nil, fmt.Errorf("plist dict has <%s> without a key", t.Name.Local)
 			}
+			v, err := decodeXMLValue(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			d.Items = append(d.Items, plistItem{Key: key, Value: v})
+			haveKey = false
 		case xml.EndElement:
-			return nil
+			return d, nil
 		}
 	}
 }
 
-// decodeArray parses a plist <array> containing <dict> elements.
-func decodeArray(dec *xml.Decoder) ([]plistDict, error) {
-	var result []plistDict
+// decodeXMLArray decodes the values of an <array> up to its end element.
+func decodeXMLArray(dec *xml.Decoder) ([]plistValue, error) {
+	var result []plistValue
 	for {
 		tok, err := dec.Token()
 		if err != nil {
 			return nil, err
 		}
-
 		switch t := tok.(type) {
 		case xml.StartElement:
-			if t.Name.Local == "dict" {
-				var d plistDict
-				if err := dec.DecodeElement(&d, &t); err != nil {
-					return nil, err
-				}
-				result = append(result, d)
-			} else {
-				if err := dec.Skip(); err != nil {
-					return nil, err
-				}
+			v, err := decodeXMLValue(dec, t)
+			if err != nil {
+				return nil, err
 			}
+			result = append(result, v)
 		case xml.EndElement:
 			return result, nil
 		}
 	}
 }
 
-// dictGet retrieves a string value by key from a plistDict.
-func dictGet(d *plistDict, key string) string {
+// dictValue retrieves a value by key.
+func dictValue(d *plistDict, key string) (plistValue, bool) {
+	if d == nil {
+		return plistValue{}, false
+	}
 	for _, item := range d.Items {
 		if item.Key == key {
-			return item.Value.String
+			return item.Value, true
 		}
 	}
-	return ""
+	return plistValue{}, false
+}
+
+// dictGet retrieves a string value by key from a plistDict.
+func dictGet(d *plistDict, key string) string {
+	v, _ := dictValue(d, key)
+	return v.String
 }
 
 // dictGetDict retrieves a child dict by key.
 func dictGetDict(d *plistDict, key string) *plistDict {
-	for _, item := range d.Items {
-		if item.Key == key {
-			return item.Value.Dict
-		}
-	}
-	return nil
+	v, _ := dictValue(d, key)
+	return v.Dict
 }
 
-// dictGetArray retrieves a child array by key.
+// dictGetArray retrieves the dicts of a child array by key. Array
+// elements of other types are skipped.
 func dictGetArray(d *plistDict, key string) []plistDict {
-	for _, item := range d.Items {
-		if item.Key == key {
-			return item.Value.Array
+	v, _ := dictValue(d, key)
+	var result []plistDict
+	for _, elem := range v.Array {
+		if elem.Dict != nil {
+			result = append(result, *elem.Dict)
 		}
 	}
-	return nil
+	return result
 }
@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// vscodeSemanticStyle is a semanticTokenColors value: either a color
+// string or a style object.
+type vscodeSemanticStyle struct {
+	Foreground    string `json:"foreground"`
+	FontStyle     string `json:"fontStyle"`
+	Bold          *bool  `json:"bold"`
+	Italic        *bool  `json:"italic"`
+	Underline     *bool  `json:"underline"`
+	Strikethrough *bool  `json:"strikethrough"`
+}
+
+// UnmarshalJSON accepts both the string and the object form.
+func (s *vscodeSemanticStyle) UnmarshalJSON(data []byte) error {
+	var color string
+	if err := json.Unmarshal(data, &color); err == nil {
+		*s = vscodeSemanticStyle{Foreground: color}
+		return nil
+	}
+	type plain vscodeSemanticStyle
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+var semanticIdent = `[A-Za-z][A-Za-z0-9_-]*`
+
+// semanticSelectorPattern matches "type.modifier*:language", where type
+// may be "*".
+var semanticSelectorPattern = regexp.MustCompile(
+	`^(\*|` + semanticIdent + `)((?:\.` + semanticIdent + `)*)(?::(` + semanticIdent + `))?$`)
+
+// ParseSemanticSelector splits a semantic token selector such as
+// "variable.readonly:java" into its token type, modifiers and language.
+// It reports false when selector is malformed.
+func ParseSemanticSelector(selector string) (tokenType string, modifiers []string, language string, ok bool) {
+	m := semanticSelectorPattern.FindStringSubmatch(strings.TrimSpace(selector))
+	if m == nil {
+		return "", nil, "", false
+	}
+	if m[2] != "" {
+		modifiers = strings.Split(m[2][1:], ".")
+	}
+	return m[1], modifiers, m[3], true
+}
+
+// mapVSCodeSemanticTokenColors converts VS Code semanticTokenColors to
+// Orchestra rules, sorted by selector. Rules with an unparseable
+// selector or no valid style are dropped.
+func mapVSCodeSemanticTokenColors(src map[string]vscodeSemanticStyle) []types.SemanticTokenColor {
+	if len(src) == 0 {
+		return nil
+	}
+	selectors := make([]string, 0, len(src))
+	for selector := range src {
+		selectors = append(selectors, selector)
+	}
+	sort.Strings(selectors)
+
+	result := make([]types.SemanticTokenColor, 0, len(src))
+	for _, selector := range selectors {
+		tokenType, modifiers, language, ok := ParseSemanticSelector(selector)
+		if !ok {
+			continue
+		}
+		vs := src[selector]
+		style := types.SemanticTokenStyle{
+			FontStyle:     vs.FontStyle,
+			Bold:          vs.Bold,
+			Italic:        vs.Italic,
+			Underline:     vs.Underline,
+			Strikethrough: vs.Strikethrough,
+		}
+		if normalized, ok := normalizeColor(vs.Foreground); ok {
+			style.Foreground = normalized
+		}
+		if style == (types.SemanticTokenStyle{}) {
+			continue
+		}
+		result = append(result, types.SemanticTokenColor{
+			Selector:  strings.TrimSpace(selector),
+			TokenType: tokenType,
+			Modifiers: modifiers,
+			Language:  language,
+			Style:     style,
+		})
+	}
+	return result
+}
+
+// FillSemanticSelectors derives the token type, modifiers and language of
+// rules that only carry a selector.
+func FillSemanticSelectors(rules []types.SemanticTokenColor) {
+	for i := range rules {
+		if rules[i].TokenType != "" {
+			continue
+		}
+		if tokenType, modifiers, language, ok := ParseSemanticSelector(rules[i].Selector); ok {
+			rules[i].TokenType = tokenType
+			rules[i].Modifiers = modifiers
+			rules[i].Language = language
+		}
+	}
+}
@@ -0,0 +1,505 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/contrast"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// ErrInvalidPackage is returned when a Sublime Text package contains no
+// color schemes.
+var ErrInvalidPackage = errors.New("invalid Sublime Text package")
+
+// sublimeScheme is the .sublime-color-scheme JSON structure.
+type sublimeScheme struct {
+	Name      string            `json:"name"`
+	Author    string            `json:"author"`
+	Variables map[string]string `json:"variables"`
+	Globals   map[string]string `json:"globals"`
+	Rules     []sublimeRule     `json:"rules"`
+}
+
+// sublimeRule is one entry of a color scheme's rules.
+type sublimeRule struct {
+	Name       string       `json:"name"`
+	Scope      string       `json:"scope"`
+	Foreground sublimeColor `json:"foreground"`
+	Background string       `json:"background"`
+	FontStyle  string       `json:"font_style"`
+}
+
+// sublimeColor is a rule foreground: a color, or an array of colors for
+// hashed syntax highlighting, of which the first is used.
+type sublimeColor string
+
+// UnmarshalJSON accepts a string or an array of strings.
+func (c *sublimeColor) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*c = sublimeColor(single)
+		return nil
+	}
+	var arr []string
+	if err := json.Unmarshal(data, &arr); err != nil {
+		return fmt.Errorf("foreground must be string or string array: %w", err)
+	}
+	if len(arr) > 0 {
+		*c = sublimeColor(arr[0])
+	}
+	return nil
+}
+
+// sublimeFontStyles maps Sublime font_style words to Orchestra font
+// styles. Words not listed, such as "glow", have no equivalent.
+var sublimeFontStyles = map[string]string{
+	"bold":               "bold",
+	"italic":             "italic",
+	"underline":          "underline",
+	"stippled_underline": "underline",
+	"squiggly_underline": "underline",
+}
+
+// ImportSublime parses a Sublime Text .sublime-color-scheme file into a
+// ThemeDef. Variables referenced as var(name) and color() adjusters are
+// evaluated to plain colors. Globals map to canonical keys like their
+// tmTheme counterparts (line_highlight as lineHighlight) and are all kept
+// under the "raw." prefix; rules become token colors.
+func ImportSublime(data []byte) (*types.ThemeDef, error) {
+	var scheme sublimeScheme
+	if err := unmarshalJSONC(data, &scheme); err != nil {
+		return nil, fmt.Errorf("invalid Sublime color scheme JSON: %w", err)
+	}
+	if len(scheme.Globals) == 0 && len(scheme.Rules) == 0 {
+		return nil, errors.New("color scheme has no globals or rules")
+	}
+
+	theme := &types.ThemeDef{
+		Name:   scheme.Name,
+		Author: scheme.Author,
+		Source: "sublime",
+		Colors: make(map[string]string),
+	}
+	if theme.Name == "" {
+		theme.Name = namelessFormats[FormatSublime]
+	}
+	theme.ID = slugify(theme.Name)
+
+	keys := make([]string, 0, len(scheme.Globals))
+	for key := range scheme.Globals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	eval := &sublimeEval{vars: scheme.Variables, expanded: make(map[string]string)}
+	for _, key := range keys {
+		value := scheme.Globals[key]
+		mapped := tmGlobalColorMap[sublimeGlobalKey(key)]
+		c, err := eval.color(value)
+		if err != nil {
+			if len(mapped) > 0 {
+				return nil, fmt.Errorf("globals.%s: %w", key, err)
+			}
+			// Not every global is a color (popup_css, shadow_width).
+			theme.Colors[colorkeys.RawPrefix+key] = value
+			continue
+		}
+		for _, canonical := range mapped {
+			theme.Colors[canonical] = c.Hex()
+		}
+		theme.Colors[colorkeys.RawPrefix+key] = c.Hex()
+	}
+
+	for i, rule := range scheme.Rules {
+		tc, err := eval.rule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+		theme.TokenColors = append(theme.TokenColors, tc)
+	}
+	theme.Type = detectThemeType(theme.Colors)
+	return theme, nil
+}
+
+// ImportSublimePackage reads a .sublime-package zip and imports the color
+// schemes in it: .sublime-color-scheme files and legacy .tmTheme plists.
+// Schemes without a name are named after their file. A scheme that fails
+// to import is reported in its ExtensionTheme; an error is returned only
+// when the archive is unreadable or has no color schemes.
+func ImportSublimePackage(data []byte) ([]ExtensionTheme, error) {
+	bundle, err := ReadZipBundle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range bundle {
+		if isSublimeSchemeFile(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: no color schemes", ErrInvalidPackage)
+	}
+	sort.Strings(names)
+
+	themes := make([]ExtensionTheme, 0, len(names))
+	for _, name := range names {
+		base := path.Base(name)
+		stem := strings.TrimSuffix(base, path.Ext(base))
+		et := ExtensionTheme{Label: stem, Path: name}
+		if strings.HasSuffix(strings.ToLower(name), ".tmtheme") {
+			et.Theme, et.Err = ImportTmTheme(bundle[name])
+		} else {
+			et.Theme, et.Err = ImportSublime(bundle[name])
+		}
+		if et.Err == nil {
+			switch et.Theme.Name {
+			case namelessFormats[FormatSublime], "Imported TextMate Theme":
+				if slugify(stem) != "" {
+					et.Theme.Name = stem
+					et.Theme.ID = slugify(stem)
+				}
+			}
+			et.Label = et.Theme.Name
+		}
+		themes = append(themes, et)
+	}
+	return themes, nil
+}
+
+func isSublimeSchemeFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, ".sublime-color-scheme") || strings.HasSuffix(lower, ".tmtheme")
+}
+
+// isSublimeScheme reports whether a JSON object looks like a Sublime
+// color scheme.
+func isSublimeScheme(probe map[string]json.RawMessage) bool {
+	_, hasGlobals := probe["globals"]
+	_, hasRules := probe["rules"]
+	return hasGlobals || hasRules
+}
+
+// sublimeGlobalKey returns the tmTheme spelling of a Sublime global:
+// line_highlight becomes lineHighlight.
+func sublimeGlobalKey(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// Limits on var() expansion. References are expanded textually, so a
+// chain of variables that each reference the previous one twice grows
+// exponentially; these bound the length of one expanded value and the
+// total size of all expanded variables.
+const (
+	maxSublimeValue     = 4 << 10
+	maxSublimeVariables = 1 << 20
+)
+
+// sublimeEval evaluates color expressions against a scheme's variables.
+type sublimeEval struct {
+	vars map[string]string
+	// expanded caches variables with their var() references expanded.
+	expanded map[string]string
+	// size is the total length of the expanded variables.
+	size int
+	// active holds the variables being expanded, to detect cycles.
+	active []string
+}
+
+// rule converts a color scheme rule to a token color.
+func (e *sublimeEval) rule(rule sublimeRule) (types.TokenColor, error) {
+	tc := types.TokenColor{
+		Name:     rule.Name,
+		Scope:    splitScope(rule.Scope),
+		Settings: make(map[string]string),
+	}
+	for _, setting := range [][2]string{
+		{"foreground", string(rule.Foreground)},
+		{"background", rule.Background},
+	} {
+		key, value := setting[0], setting[1]
+		if value == "" {
+			continue
+		}
+		c, err := e.color(value)
+		if err != nil {
+			return tc, fmt.Errorf("%s: %w", key, err)
+		}
+		tc.Settings[key] = c.Hex()
+	}
+
+	var styles []string
+	seen := make(map[string]bool)
+	for _, word := range strings.Fields(rule.FontStyle) {
+		if style, ok := sublimeFontStyles[word]; ok && !seen[style] {
+			seen[style] = true
+			styles = append(styles, style)
+		}
+	}
+	if len(styles) > 0 {
+		tc.Settings["fontStyle"] = strings.Join(styles, " ")
+	}
+	return tc, nil
+}
+
+// color evaluates a color value that may reference variables.
+func (e *sublimeEval) color(value string) (color.Color, error) {
+	expanded, err := e.expand(value)
+	if err != nil {
+		return color.Color{}, err
+	}
+	return evalSublimeColor(expanded)
+}
+
+// expand replaces every var(name) in value with the variable's expanded
+// value.
+func (e *sublimeEval) expand(value string) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(value, "var(")
+		if i < 0 {
+			b.WriteString(value)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(value[i:], ')')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated var() in %q", value)
+		}
+		name := strings.TrimSpace(value[i+len("var(") : i+end])
+		sub, err := e.variable(name)
+		if err != nil {
+			return "", err
+		}
+		if b.Len()+i+len(sub) > maxSublimeValue {
+			return "", fmt.Errorf("variable %q expands beyond %d bytes", name, maxSublimeValue)
+		}
+		b.WriteString(value[:i])
+		b.WriteString(sub)
+		value = value[i+end+1:]
+	}
+}
+
+// variable returns the expanded value of the named variable.
+func (e *sublimeEval) variable(name string) (string, error) {
+	if v, ok := e.expanded[name]; ok {
+		return v, nil
+	}
+	raw, ok := e.vars[name]
+	if !ok {
+		return "", fmt.Errorf("unknown variable %q", name)
+	}
+	for i, active := range e.active {
+		if active == name {
+			chain := append(append([]string(nil), e.active[i:]...), name)
+			return "", fmt.Errorf("variable cycle: %s", strings.Join(chain, " -> "))
+		}
+	}
+	e.active = append(e.active, name)
+	v, err := e.expand(raw)
+	e.active = e.active[:len(e.active)-1]
+	if err != nil {
+		return "", err
+	}
+	if e.size += len(v); e.size > maxSublimeVariables {
+		return "", fmt.Errorf("variables expand beyond %d bytes", maxSublimeVariables)
+	}
+	e.expanded[name] = v
+	return v, nil
+}
+
+// evalSublimeColor evaluates a CSS color, or a color(base adjuster...)
+// expression with the alpha()/a(), lightness()/l(), saturation()/s(),
+// blend(), blenda() and min-contrast() adjusters.
+func evalSublimeColor(expr string) (color.Color, error) {
+	expr = strings.TrimSpace(expr)
+	body, ok := cutFunction(expr, "color")
+	if !ok {
+		return color.Parse(expr)
+	}
+	fields := sublimeFields(body)
+	if len(fields) == 0 {
+		return color.Color{}, fmt.Errorf("empty color() in %q", expr)
+	}
+	c, err := evalSublimeColor(fields[0])
+	if err != nil {
+		return color.Color{}, err
+	}
+	for _, adj := range fields[1:] {
+		open := strings.IndexByte(adj, '(')
+		if open < 0 || !strings.HasSuffix(adj, ")") {
+			return color.Color{}, fmt.Errorf("invalid color adjuster %q", adj)
+		}
+		c, err = adjustSublimeColor(c, strings.ToLower(adj[:open]), adj[open+1:len(adj)-1])
+		if err != nil {
+			return color.Color{}, fmt.Errorf("%s: %w", adj, err)
+		}
+	}
+	return c, nil
+}
+
+// adjustSublimeColor applies one color() adjuster to c.
+func adjustSublimeColor(c color.Color, name, arg string) (color.Color, error) {
+	switch name {
+	case "alpha", "a":
+		a, err := adjustComponent(c.A, arg)
+		if err != nil {
+			return c, err
+		}
+		c.A = math.Max(0, math.Min(1, a))
+		return c, nil
+	case "lightness", "l", "saturation", "s":
+		h, s, l := c.HSL()
+		var err error
+		if name[0] == 'l' {
+			l, err = adjustComponent(l, arg)
+		} else {
+			s, err = adjustComponent(s, arg)
+		}
+		if err != nil {
+			return c, err
+		}
+		return color.FromHSL(h, s, l, c.A), nil
+	case "blend", "blenda":
+		return blendSublimeColor(c, arg, name == "blenda")
+	case "min-contrast":
+		fields := sublimeFields(arg)
+		if len(fields) != 2 {
+			return c, errors.New("expected a color and a ratio")
+		}
+		bg, err := evalSublimeColor(fields[0])
+		if err != nil {
+			return c, err
+		}
+		ratio, err := color.ParseFinite(fields[1])
+		if err != nil {
+			return c, fmt.Errorf("invalid ratio %q", fields[1])
+		}
+		adjusted, _ := contrast.Adjust(c, bg, ratio)
+		return adjusted, nil
+	default:
+		return c, fmt.Errorf("unsupported color adjuster %q", name)
+	}
+}
+
+// blendSublimeColor mixes c with the color given in arg, where the
+// percentage is the share of c that is kept. blend keeps the alpha of c;
+// blenda mixes it too. Colors are mixed in RGB unless "hsl" follows the
+// percentage.
+func blendSublimeColor(c color.Color, arg string, withAlpha bool) (color.Color, error) {
+	fields := sublimeFields(arg)
+	if len(fields) != 2 && len(fields) != 3 {
+		return c, errors.New("expected a color and a percentage")
+	}
+	other, err := evalSublimeColor(fields[0])
+	if err != nil {
+		return c, err
+	}
+	pct, ok := strings.CutSuffix(fields[1], "%")
+	share, err := color.ParseFinite(pct)
+	if !ok || err != nil {
+		return c, fmt.Errorf("invalid percentage %q", fields[1])
+	}
+	share = math.Max(0, math.Min(1, share/100))
+
+	space := "rgb"
+	if len(fields) == 3 {
+		space = strings.ToLower(fields[2])
+	}
+	var mixed color.Color
+	switch space {
+	case "rgb":
+		mixed = color.Mix(other, c, share)
+	case "hsl":
+		h1, s1, l1 := other.HSL()
+		h2, s2, l2 := c.HSL()
+		dh := math.Mod(h2-h1+540, 360) - 180
+		mixed = color.FromHSL(h1+dh*share, s1+(s2-s1)*share, l1+(l2-l1)*share, other.A+(c.A-other.A)*share)
+	default:
+		return c, fmt.Errorf("unsupported blend space %q", space)
+	}
+	if !withAlpha {
+		mixed.A = c.A
+	}
+	return mixed, nil
+}
+
+// adjustComponent applies a lightness, saturation or alpha argument to
+// cur, a fraction in [0, 1]. The argument is an absolute value or "+",
+// "-" or "*" followed by whitespace and an amount; values are fractions
+// or percentages.
+func adjustComponent(cur float64, arg string) (float64, error) {
+	arg = strings.TrimSpace(arg)
+	op := byte(0)
+	if len(arg) > 1 && strings.IndexByte("+-*", arg[0]) >= 0 && (arg[1] == ' ' || arg[1] == '\t') {
+		op = arg[0]
+		arg = strings.TrimSpace(arg[1:])
+	}
+	num, pct := strings.CutSuffix(arg, "%")
+	v, err := color.ParseFinite(num)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", arg)
+	}
+	if pct {
+		v /= 100
+	}
+	switch op {
+	case '+':
+		return cur + v, nil
+	case '-':
+		return cur - v, nil
+	case '*':
+		return cur * v, nil
+	default:
+		return v, nil
+	}
+}
+
+// cutFunction returns the arguments of a call to fn spanning all of expr.
+func cutFunction(expr, fn string) (string, bool) {
+	if len(expr) < len(fn)+2 || !strings.EqualFold(expr[:len(fn)+1], fn+"(") || !strings.HasSuffix(expr, ")") {
+		return "", false
+	}
+	return expr[len(fn)+1 : len(expr)-1], true
+}
+
+// sublimeFields splits s at whitespace outside parentheses.
+func sublimeFields(s string) []string {
+	var fields []string
+	depth, start := 0, -1
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case (c == ' ' || c == '\t' || c == '\n') && depth == 0:
+			if start >= 0 {
+				fields = append(fields, s[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, s[start:])
+	}
+	return fields
+}
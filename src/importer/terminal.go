@@ -0,0 +1,176 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/color"
+
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// vscodeTerminalMap maps VS Code terminal color keys to TerminalPalette
+// fields.
+var vscodeTerminalMap = map[string]string{
+	"terminal.ansiBlack":           "black",
+	"terminal.ansiRed":             "red",
+	"terminal.ansiGreen":           "green",
+	"terminal.ansiYellow":          "yellow",
+	"terminal.ansiBlue":            "blue",
+	"terminal.ansiMagenta":         "magenta",
+	"terminal.ansiCyan":            "cyan",
+	"terminal.ansiWhite":           "white",
+	"terminal.ansiBrightBlack":     "bright_black",
+	"terminal.ansiBrightRed":       "bright_red",
+	"terminal.ansiBrightGreen":     "bright_green",
+	"terminal.ansiBrightYellow":    "bright_yellow",
+	"terminal.ansiBrightBlue":      "bright_blue",
+	"terminal.ansiBrightMagenta":   "bright_magenta",
+	"terminal.ansiBrightCyan":      "bright_cyan",
+	"terminal.ansiBrightWhite":     "bright_white",
+	"terminal.background":          "background",
+	"terminal.foreground":          "foreground",
+	"terminalCursor.foreground":    "cursor",
+	"terminal.selectionBackground": "selection",
+}
+
+// itermTerminalMap maps iTerm2 color names to TerminalPalette fields.
+var itermTerminalMap = func() map[string]string {
+	m := map[string]string{
+		"Background Color": "background",
+		"Foreground Color": "foreground",
+		"Cursor Color":     "cursor",
+		"Selection Color":  "selection",
+	}
+	for i, name := range types.TerminalColorNames[:16] {
+		m[fmt.Sprintf("Ansi %d Color", i)] = name
+	}
+	return m
+}()
+
+// terminalMaps holds the terminal key map of each source format whose
+// raw keys carry terminal colors.
+var terminalMaps = map[string]map[string]string{
+	"vscode":      vscodeTerminalMap,
+	"itermcolors": itermTerminalMap,
+}
+
+// TerminalColorMap returns a copy of the mapping from source color keys
+// to TerminalPalette field names for the given source format, or nil if
+// the format has no terminal colors.
+func TerminalColorMap(source string) map[string]string {
+	m, ok := terminalMaps[source]
+	if !ok {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for key, field := range m {
+		out[key] = field
+	}
+	return out
+}
+
+// TerminalFromRaw builds a terminal palette from the "raw." keys that an
+// importer of the given source format preserved in colors. It returns nil
+// when none of them is a valid terminal color.
+func TerminalFromRaw(source string, colors map[string]string) *types.TerminalPalette {
+	var palette *types.TerminalPalette
+	for key, field := range terminalMaps[source] {
+		value, ok := normalizeColor(colors[colorkeys.RawPrefix+key])
+		if !ok {
+			continue
+		}
+		if palette == nil {
+			palette = &types.TerminalPalette{}
+		}
+		*palette.Field(field) = value
+	}
+	return palette
+}
+
+// terminalColor parses a color as terminal emulators write them: CSS
+// syntax, "0xRRGGBB" (Alacritty) or X11 "rgb:RR/GG/BB" with one to four
+// hex digits per channel (kitty, Xresources).
+func terminalColor(value string) (string, bool) {
+	v := strings.TrimSpace(value)
+	switch lower := strings.ToLower(v); {
+	case strings.HasPrefix(lower, "0x"):
+		v = "#" + v[2:]
+	case strings.HasPrefix(lower, "rgb:"):
+		return x11Color(v[4:])
+	}
+	return normalizeColor(v)
+}
+
+// x11Color parses the channels of an X11 "rgb:" color.
+func x11Color(spec string) (string, bool) {
+	parts := strings.Split(spec, "/")
+	if len(parts) != 3 {
+		return "", false
+	}
+	var ch [3]float64
+	for i, part := range parts {
+		if len(part) < 1 || len(part) > 4 {
+			return "", false
+		}
+		n, err := strconv.ParseUint(part, 16, 16)
+		if err != nil {
+			return "", false
+		}
+		ch[i] = float64(n) / float64(uint64(1)<<(4*len(part))-1)
+	}
+	return color.Color{R: ch[0], G: ch[1], B: ch[2], A: 1}.Hex(), true
+}
+
+// terminalTheme builds a theme from the terminal palette of a terminal
+// emulator color scheme. Palette fields are parsed with terminalColor;
+// the background, foreground, cursor and selection also set the
+// corresponding editor keys, so the rest of the UI can be derived from
+// them. The scheme must define at least one ANSI color.
+func terminalTheme(name, source string, palette *types.TerminalPalette) (*types.ThemeDef, error) {
+	ansi := false
+	for i, field := range types.TerminalColorNames {
+		value := palette.Field(field)
+		if *value == "" {
+			continue
+		}
+		normalized, ok := terminalColor(*value)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s color %q", field, *value)
+		}
+		*value = normalized
+		ansi = ansi || i < 16
+	}
+	if !ansi {
+		return nil, errors.New("color scheme defines no ANSI colors")
+	}
+
+	theme := &types.ThemeDef{
+		Name:     name,
+		Source:   source,
+		Colors:   make(map[string]string),
+		Terminal: palette,
+	}
+	theme.ID = slugify(theme.Name)
+	for field, keys := range terminalEditorKeys {
+		if value := *palette.Field(field); value != "" {
+			for _, key := range keys {
+				theme.Colors[key] = value
+			}
+		}
+	}
+	theme.Type = detectThemeType(theme.Colors)
+	return theme, nil
+}
+
+// terminalEditorKeys maps terminal palette fields to the canonical keys
+// terminalTheme sets from them.
+var terminalEditorKeys = map[string][]string{
+	"background": {colorkeys.Background, colorkeys.EditorBackground},
+	"foreground": {colorkeys.Foreground, colorkeys.EditorForeground},
+	"cursor":     {colorkeys.EditorCursor},
+	"selection":  {colorkeys.EditorSelection},
+}
@@ -1,10 +1,10 @@
 package importer
 
 import (
-	"encoding/xml"
 	"fmt"
-	"strings"
 
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
 	"github.com/orchestra-mcp/themes/src/types"
 )
 
@@ -13,19 +13,33 @@ const (
 	themeLight = "light"
 )
 
-// tmTheme global color mappings to Orchestra keys.
-var tmGlobalColorMap = map[string]string{
-	"background":    "bg-primary",
-	"foreground":    "text-primary",
-	"caret":         "caret",
-	"selection":     "bg-selection",
-	"lineHighlight": "bg-line-highlight",
+// tmTheme global color mappings to canonical Orchestra keys.
+var tmGlobalColorMap = map[string][]string{
+	"background":    {colorkeys.EditorBackground, colorkeys.Background},
+	"foreground":    {colorkeys.EditorForeground, colorkeys.Foreground},
+	"caret":         {colorkeys.EditorCursor},
+	"selection":     {colorkeys.EditorSelection},
+	"lineHighlight": {colorkeys.EditorLineHighlight},
 }
 
-// ImportTmTheme parses a .tmTheme plist XML file into a ThemeDef.
+// TmGlobalColorMap returns a copy of the mapping from tmTheme global
+// setting names to canonical keys used by ImportTmTheme.
+func TmGlobalColorMap() map[string][]string {
+	out := make(map[string][]string, len(tmGlobalColorMap))
+	for key, mapped := range tmGlobalColorMap {
+		out[key] = append([]string(nil), mapped...)
+	}
+	return out
+}
+
+// ImportTmTheme parses a .tmTheme plist, in XML or binary form, into a
+// ThemeDef.
 func ImportTmTheme(data []byte) (*types.ThemeDef, error) {
-	var root plistRoot
-	if err := xml.Unmarshal(data, &root); err != nil {
+	root, err := decodePlistDict(data)
+	if err != nil {
+		if isBinaryPlist(data) {
+			return nil, fmt.Errorf("invalid tmTheme binary plist: %w", err)
+		}
 		return nil, fmt.Errorf("invalid tmTheme XML: %w", err)
 	}
 
@@ -34,14 +48,14 @@ func ImportTmTheme(data []byte) (*types.ThemeDef, error) {
 		Colors: make(map[string]string),
 	}
 
-	theme.Name = dictGet(&root.Dict, "name")
+	theme.Name = dictGet(root, "name")
 	if theme.Name == "" {
 		theme.Name = "Imported TextMate Theme"
 	}
 	theme.ID = slugify(theme.Name)
-	theme.Author = dictGet(&root.Dict, "author")
+	theme.Author = dictGet(root, "author")
 
-	settings := dictGetArray(&root.Dict, "settings")
+	settings := dictGetArray(root, "settings")
 	if len(settings) == 0 {
 		return nil, fmt.Errorf("tmTheme has no settings array")
 	}
@@ -69,10 +83,12 @@ func extractGlobalColors(settings []plistDict, theme *types.ThemeDef) {
 		if value == "" {
 			continue
 		}
-		if mapped, ok := tmGlobalColorMap[item.Key]; ok {
-			theme.Colors[mapped] = value
+		if normalized, ok := normalizeColor(value); ok {
+			for _, mapped := range tmGlobalColorMap[item.Key] {
+				theme.Colors[mapped] = normalized
+			}
 		}
-		theme.Colors["raw."+item.Key] = value
+		theme.Colors[colorkeys.RawPrefix+item.Key] = value
 	}
 }
 
@@ -98,6 +114,7 @@ func extractTokenColors(settings []plistDict) []types.TokenColor {
 				settingsMap[item.Key] = item.Value.String
 			}
 		}
+		normalizeTokenSettings(settingsMap)
 
 		var scopes []string
 		if scopeStr != "" {
@@ -116,9 +133,12 @@ func extractTokenColors(settings []plistDict) []types.TokenColor {
 
 // detectThemeType guesses light vs dark from the background color.
 func detectThemeType(colors map[string]string) string {
-	bg := colors["bg-primary"]
+	bg := colors[colorkeys.Background]
 	if bg == "" {
-		bg = colors["raw.background"]
+		bg = colors[colorkeys.EditorBackground]
+	}
+	if bg == "" {
+		bg = colors[colorkeys.RawPrefix+"background"]
 	}
 	if bg == "" {
 		return themeDark
@@ -129,28 +149,12 @@ func detectThemeType(colors map[string]string) string {
 	return themeDark
 }
 
-// isLightColor returns true if a hex color is considered light.
-func isLightColor(hex string) bool {
-	hex = strings.TrimPrefix(hex, "#")
-	if len(hex) < 6 {
+// isLightColor reports whether a color value is considered light.
+// Unparseable values are treated as dark.
+func isLightColor(value string) bool {
+	c, err := color.Parse(value)
+	if err != nil {
 		return false
 	}
-	r := hexVal(hex[0])*16 + hexVal(hex[1])
-	g := hexVal(hex[2])*16 + hexVal(hex[3])
-	b := hexVal(hex[4])*16 + hexVal(hex[5])
-	luminance := (r*299 + g*587 + b*114) / 1000
-	return luminance > 128
-}
-
-func hexVal(c byte) int {
-	switch {
-	case c >= '0' && c <= '9':
-		return int(c - '0')
-	case c >= 'a' && c <= 'f':
-		return int(c-'a') + 10
-	case c >= 'A' && c <= 'F':
-		return int(c-'A') + 10
-	default:
-		return 0
-	}
+	return c.IsLight()
 }
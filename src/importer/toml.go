@@ -0,0 +1,207 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// readTOML reads the subset of TOML used by terminal emulator configs:
+// tables, dotted keys, inline tables, strings, numbers and booleans.
+// Scalars are returned under their full dotted key ("colors.normal.red"),
+// strings unquoted and other values as written. Arrays and arrays of
+// tables are skipped. Errors are *SyntaxError values.
+func readTOML(data []byte) (map[string]string, error) {
+	out := make(map[string]string)
+	lines := strings.Split(string(bytes.TrimPrefix(data, utf8BOM)), "\n")
+	table := ""
+	inArrayTable := false
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(stripHashComment(lines[i]))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[[") {
+			inArrayTable = true
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, &SyntaxError{Line: i + 1, Column: 1, Msg: "unterminated table header"}
+			}
+			table = tomlKey(line[1 : len(line)-1])
+			inArrayTable = false
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, &SyntaxError{Line: i + 1, Column: 1, Msg: "expected key = value"}
+		}
+		value = strings.TrimSpace(value)
+		// Arrays are skipped; multi-line ones continue until their
+		// brackets balance.
+		if strings.HasPrefix(value, "[") {
+			for depth := bracketDepth(value); depth > 0 && i+1 < len(lines); {
+				i++
+				depth += bracketDepth(stripHashComment(lines[i]))
+			}
+			continue
+		}
+		if inArrayTable {
+			continue
+		}
+		if err := tomlValue(out, joinKey(table, tomlKey(key)), value); err != nil {
+			return nil, &SyntaxError{Line: i + 1, Column: 1, Msg: err.Error()}
+		}
+	}
+	return out, nil
+}
+
+// tomlValue stores value, and every scalar in it if it is an inline
+// table, under key.
+func tomlValue(out map[string]string, key, value string) error {
+	switch {
+	case value == "":
+		return fmt.Errorf("missing value for %s", key)
+	case strings.HasPrefix(value, "["):
+		// Arrays nested in inline tables are skipped too.
+		return nil
+	case strings.HasPrefix(value, "{"):
+		if !strings.HasSuffix(value, "}") {
+			return fmt.Errorf("unterminated inline table %s", key)
+		}
+		for _, member := range splitTopLevel(value[1:len(value)-1], ',') {
+			if strings.TrimSpace(member) == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(member, "=")
+			if !ok {
+				return fmt.Errorf("expected key = value in inline table %s", key)
+			}
+			if err := tomlValue(out, joinKey(key, tomlKey(k)), strings.TrimSpace(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	s, err := unquoteScalar(value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	out[key] = s
+	return nil
+}
+
+// tomlKey normalizes a possibly dotted and quoted key.
+func tomlKey(key string) string {
+	parts := splitTopLevel(key, '.')
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if s, err := unquoteScalar(part); err == nil {
+			part = s
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, ".")
+}
+
+// unquoteScalar returns the contents of a double-quoted (with escapes)
+// or single-quoted string, or the trimmed value of an unquoted scalar.
+func unquoteScalar(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case strings.HasPrefix(value, `"`):
+		return strconv.Unquote(value)
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return "", errors.New("unterminated string")
+		}
+		// YAML escapes a quote in a single-quoted string by doubling it;
+		// TOML literal strings cannot contain one.
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), nil
+	}
+	return value, nil
+}
+
+// joinKey joins a table path and a key.
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// stripHashComment removes a "#" comment that is outside quotes.
+func stripHashComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// splitTopLevel splits s at sep where it is outside quotes, brackets and
+// braces.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// bracketDepth returns how many brackets and braces opened in s outside
+// quotes are still unclosed.
+func bracketDepth(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth
+}
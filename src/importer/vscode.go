@@ -5,19 +5,52 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/orchestra-mcp/themes/src/colorkeys"
 	"github.com/orchestra-mcp/themes/src/types"
 )
 
-// vscodeColorMap maps VS Code color keys to Orchestra color keys.
-var vscodeColorMap = map[string]string{
-	"editor.background":              "bg-primary",
-	"editor.foreground":              "text-primary",
-	"sideBar.background":             "bg-secondary",
-	"activityBar.background":         "bg-tertiary",
-	"statusBar.background":           "bg-accent",
-	"titleBar.activeBackground":      "bg-header",
-	"focusBorder":                    "border-focus",
-	"list.activeSelectionBackground": "bg-selection",
+// vscodeColorMap maps VS Code color keys to canonical Orchestra color
+// keys. A source key may populate several canonical keys; the first one
+// listed is the key it corresponds to most directly.
+var vscodeColorMap = map[string][]string{
+	"editor.background":                     {colorkeys.EditorBackground, colorkeys.Background},
+	"editor.foreground":                     {colorkeys.EditorForeground, colorkeys.Foreground},
+	"editorCursor.foreground":               {colorkeys.EditorCursor},
+	"editor.selectionBackground":            {colorkeys.EditorSelection},
+	"editor.lineHighlightBackground":        {colorkeys.EditorLineHighlight},
+	"sideBar.background":                    {colorkeys.SidebarBackground},
+	"sideBar.foreground":                    {colorkeys.SidebarForeground},
+	"activityBar.background":                {colorkeys.ActivityBarBackground},
+	"activityBar.foreground":                {colorkeys.ActivityBarForeground},
+	"statusBar.background":                  {colorkeys.StatusBarBackground},
+	"statusBar.foreground":                  {colorkeys.StatusBarForeground},
+	"titleBar.activeBackground":             {colorkeys.TitleBarBackground},
+	"titleBar.activeForeground":             {colorkeys.TitleBarForeground},
+	"focusBorder":                           {colorkeys.FocusBorder},
+	"list.activeSelectionBackground":        {colorkeys.ListSelection},
+	"panel.border":                          {colorkeys.Border},
+	"input.background":                      {colorkeys.InputBackground},
+	"input.foreground":                      {colorkeys.InputForeground},
+	"input.border":                          {colorkeys.InputBorder},
+	"button.background":                     {colorkeys.ButtonBackground, colorkeys.Primary},
+	"button.foreground":                     {colorkeys.ButtonForeground},
+	"descriptionForeground":                 {colorkeys.Secondary},
+	"badge.background":                      {colorkeys.Accent},
+	"errorForeground":                       {colorkeys.Error},
+	"editorWarning.foreground":              {colorkeys.Warning},
+	"editorInfo.foreground":                 {colorkeys.Info},
+	"gitDecoration.addedResourceForeground": {colorkeys.Success},
+}
+
+// VSCodeColorMap returns a copy of the mapping from VS Code color keys to
+// canonical keys used by ImportVSCode, for exporters that need the
+// reverse direction.
+func VSCodeColorMap() map[string][]string {
+	out := make(map[string][]string, len(vscodeColorMap))
+	for key, mapped := range vscodeColorMap {
+		out[key] = append([]string(nil), mapped...)
+	}
+	return out
 }
 
 // vscodeThemeFile represents the top-level VS Code theme JSON structure.
@@ -27,6 +60,9 @@ type vscodeThemeFile struct {
 	Include     string                  `json:"include,omitempty"`
 	Colors      map[string]string       `json:"colors"`
 	TokenColors []vscodeTokenColorEntry `json:"tokenColors"`
+
+	SemanticHighlighting *bool                          `json:"semanticHighlighting"`
+	SemanticTokenColors  map[string]vscodeSemanticStyle `json:"semanticTokenColors"`
 }
 
 // vscodeTokenColorEntry represents a single token color rule.
@@ -67,19 +103,40 @@ func splitScope(raw string) []string {
 	return result
 }
 
-// vscodeTokenSettings holds foreground and fontStyle for a token rule.
+// vscodeTokenSettings holds the colors and font style of a token rule.
 type vscodeTokenSettings struct {
 	Foreground string `json:"foreground"`
+	Background string `json:"background"`
 	FontStyle  string `json:"fontStyle"`
 }
 
-// ImportVSCode parses a VS Code JSON theme file into a ThemeDef.
+// ImportVSCode parses a VS Code JSON theme file into a ThemeDef. Comments
+// and trailing commas (JSONC) are accepted; decoding errors carry the
+// line and column as a *SyntaxError. A theme with an "include" reference
+// is incomplete on its own and fails with ErrIncludeUnresolved; import it
+// with ImportVSCodeBundle together with the files it includes.
 func ImportVSCode(data []byte) (*types.ThemeDef, error) {
+	vsTheme, err := decodeVSCode(data)
+	if err != nil {
+		return nil, err
+	}
+	if vsTheme.Include != "" {
+		return nil, fmt.Errorf("%w: %q; import it as a bundle with the included files", ErrIncludeUnresolved, vsTheme.Include)
+	}
+	return vscodeToTheme(vsTheme), nil
+}
+
+// decodeVSCode unmarshals a VS Code theme file.
+func decodeVSCode(data []byte) (*vscodeThemeFile, error) {
 	var vsTheme vscodeThemeFile
-	if err := json.Unmarshal(data, &vsTheme); err != nil {
+	if err := unmarshalJSONC(data, &vsTheme); err != nil {
 		return nil, fmt.Errorf("invalid VS Code theme JSON: %w", err)
 	}
+	return &vsTheme, nil
+}
 
+// vscodeToTheme converts a decoded VS Code theme file to a ThemeDef.
+func vscodeToTheme(vsTheme *vscodeThemeFile) *types.ThemeDef {
 	theme := &types.ThemeDef{
 		Name:   vsTheme.Name,
 		Type:   normalizeThemeType(vsTheme.Type),
@@ -87,29 +144,30 @@ func ImportVSCode(data []byte) (*types.ThemeDef, error) {
 		Colors: make(map[string]string),
 	}
 
-	if vsTheme.Include != "" {
-		theme.Colors["_include"] = vsTheme.Include
-	}
-
 	mapVSCodeColors(vsTheme.Colors, theme.Colors)
+	theme.Terminal = TerminalFromRaw(theme.Source, theme.Colors)
 	theme.TokenColors = mapVSCodeTokenColors(vsTheme.TokenColors)
+	theme.SemanticHighlighting = vsTheme.SemanticHighlighting
+	theme.SemanticTokenColors = mapVSCodeSemanticTokenColors(vsTheme.SemanticTokenColors)
 
 	if theme.Name == "" {
 		theme.Name = "Imported VS Code Theme"
 	}
 	theme.ID = slugify(theme.Name)
 
-	return theme, nil
+	return theme
 }
 
 // mapVSCodeColors maps VS Code color keys to Orchestra keys
 // and stores unmapped keys under the "raw." prefix.
 func mapVSCodeColors(src, dst map[string]string) {
 	for key, value := range src {
-		if mapped, ok := vscodeColorMap[key]; ok {
-			dst[mapped] = value
+		if normalized, ok := normalizeColor(value); ok {
+			for _, mapped := range vscodeColorMap[key] {
+				dst[mapped] = normalized
+			}
 		}
-		dst["raw."+key] = value
+		dst[colorkeys.RawPrefix+key] = value
 	}
 }
 
@@ -124,9 +182,13 @@ func mapVSCodeTokenColors(entries []vscodeTokenColorEntry) []types.TokenColor {
 		if entry.Settings.Foreground != "" {
 			settings["foreground"] = entry.Settings.Foreground
 		}
+		if entry.Settings.Background != "" {
+			settings["background"] = entry.Settings.Background
+		}
 		if entry.Settings.FontStyle != "" {
 			settings["fontStyle"] = entry.Settings.FontStyle
 		}
+		normalizeTokenSettings(settings)
 		if len(settings) == 0 && len(entry.Scope) == 0 {
 			continue
 		}
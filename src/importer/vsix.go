@@ -0,0 +1,264 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// ErrInvalidExtension is returned when a VS Code extension has no
+// readable package.json or contributes no themes.
+var ErrInvalidExtension = errors.New("invalid VS Code extension")
+
+// vsixRoot is the directory holding the extension inside a .vsix.
+const vsixRoot = "extension"
+
+// Extension is a VS Code extension and the themes it contributes.
+type Extension struct {
+	Name        string           `json:"name"`
+	Publisher   string           `json:"publisher,omitempty"`
+	DisplayName string           `json:"display_name,omitempty"`
+	Version     string           `json:"version,omitempty"`
+	Themes      []ExtensionTheme `json:"themes"`
+}
+
+// ExtensionTheme is one entry of an extension's contributes.themes, or a
+// color scheme of a Sublime Text package. Err is set instead of Theme
+// when the theme file could not be imported.
+type ExtensionTheme struct {
+	Label string          `json:"label"`
+	Path  string          `json:"path"`
+	Theme *types.ThemeDef `json:"-"`
+	Err   error           `json:"-"`
+}
+
+// extensionManifest is the subset of package.json read by ImportExtension.
+type extensionManifest struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Publisher   string `json:"publisher"`
+	Version     string `json:"version"`
+	Contributes struct {
+		Themes []struct {
+			ID      string `json:"id"`
+			Label   string `json:"label"`
+			UITheme string `json:"uiTheme"`
+			Path    string `json:"path"`
+		} `json:"themes"`
+	} `json:"contributes"`
+}
+
+// ImportVSIX reads a .vsix extension package from memory and imports the
+// themes it contributes.
+func ImportVSIX(data []byte) (*Extension, error) {
+	bundle, err := ReadZipBundle(data)
+	if err != nil {
+		return nil, err
+	}
+	return ImportExtension(bundle, vsixRoot)
+}
+
+// ImportExtensionDir imports the themes of an extension unpacked in dir,
+// such as one installed under ~/.vscode/extensions. Only the manifest,
+// its localization and the contributed theme files with their include
+// chains are read, so grammars and other large files of the extension
+// do not count against the bundle limits, and directories of extensions
+// without themes are cheap to reject.
+func ImportExtensionDir(dir string) (*Extension, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: no package.json", ErrInvalidExtension)
+		}
+		return nil, err
+	}
+	manifest, err := parseManifest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := Bundle{"package.json": data}
+	if nls, err := os.ReadFile(filepath.Join(dir, "package.nls.json")); err == nil {
+		bundle["package.nls.json"] = nls
+	}
+	var pending []string
+	for _, contrib := range manifest.Contributes.Themes {
+		pending = append(pending, CleanPath(contrib.Path))
+	}
+	var total int64
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		if _, ok := bundle[name]; ok {
+			continue
+		}
+		// Missing files are reported per theme by ImportExtension.
+		content, err := readBundleFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		total += int64(len(content))
+		if total > maxBundleSize {
+			return nil, fmt.Errorf("theme files in %s exceed %d bytes", dir, maxBundleSize)
+		}
+		bundle[name] = content
+		if file, err := decodeVSCode(content); err == nil && file.Include != "" {
+			pending = append(pending, includePath(name, file.Include))
+		}
+	}
+	return ImportExtension(bundle, "")
+}
+
+// readBundleFile reads a regular file of at most maxBundleFileSize bytes.
+func readBundleFile(name string) ([]byte, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", name)
+	}
+	if info.Size() > maxBundleFileSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, maxBundleFileSize)
+	}
+	return os.ReadFile(name)
+}
+
+// ImportExtension imports the themes declared in the package.json found
+// in root of bundle. Theme files may be VS Code JSON, following include
+// references within the bundle, or tmTheme plists. The contributed label
+// becomes the theme name and ID, uiTheme the type, and the publisher and
+// display name the author and description where the file sets none.
+// Labels of the form "%key%" are looked up in package.nls.json.
+//
+// A theme that fails to import is reported in its ExtensionTheme; an
+// error is returned only when the manifest itself is unusable.
+func ImportExtension(bundle Bundle, root string) (*Extension, error) {
+	root = CleanPath(root)
+	data, ok := bundle[path.Join(root, "package.json")]
+	if !ok {
+		return nil, fmt.Errorf("%w: no package.json", ErrInvalidExtension)
+	}
+	manifest, err := parseManifest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	nls := readNLS(bundle[path.Join(root, "package.nls.json")])
+	ext := &Extension{
+		Name:        manifest.Name,
+		Publisher:   manifest.Publisher,
+		DisplayName: localize(manifest.DisplayName, nls),
+		Version:     manifest.Version,
+	}
+	for _, contrib := range manifest.Contributes.Themes {
+		et := ExtensionTheme{
+			Label: localize(contrib.Label, nls),
+			Path:  contrib.Path,
+		}
+		file := CleanPath(path.Join(root, contrib.Path))
+		et.Theme, et.Err = importContributedTheme(bundle, file)
+		if et.Err == nil {
+			ext.apply(et.Theme, et.Label, localize(contrib.ID, nls), contrib.UITheme)
+		}
+		ext.Themes = append(ext.Themes, et)
+	}
+	return ext, nil
+}
+
+// parseManifest decodes package.json, requiring at least one contributed
+// theme.
+func parseManifest(data []byte) (*extensionManifest, error) {
+	var manifest extensionManifest
+	if err := unmarshalJSONC(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: package.json: %w", ErrInvalidExtension, err)
+	}
+	if len(manifest.Contributes.Themes) == 0 {
+		return nil, fmt.Errorf("%w: package.json contributes no themes", ErrInvalidExtension)
+	}
+	return &manifest, nil
+}
+
+// importContributedTheme imports the theme file at name, detecting
+// whether it is a tmTheme or VS Code JSON.
+func importContributedTheme(bundle Bundle, name string) (*types.ThemeDef, error) {
+	data, ok := bundle[name]
+	if !ok {
+		return nil, fmt.Errorf("theme file %s not found in extension", name)
+	}
+	if DetectFormat(data) == FormatTmTheme {
+		return ImportTmTheme(data)
+	}
+	return ImportVSCodeBundle(bundle, name)
+}
+
+// apply fills theme fields from the extension manifest.
+func (ext *Extension) apply(theme *types.ThemeDef, label, id, uiTheme string) {
+	if label != "" {
+		theme.Name = label
+	}
+	if id == "" {
+		id = theme.Name
+	}
+	if slug := slugify(id); slug != "" {
+		theme.ID = slug
+	}
+	if uiTheme != "" {
+		theme.Type = normalizeThemeType(uiTheme)
+	}
+	if theme.Author == "" {
+		theme.Author = ext.Publisher
+	}
+	if theme.Description == "" {
+		theme.Description = ext.DisplayName
+	}
+}
+
+// readNLS decodes a package.nls.json localization file. Unreadable files
+// yield an empty table.
+func readNLS(data []byte) map[string]string {
+	nls := make(map[string]string)
+	if len(data) == 0 {
+		return nls
+	}
+	var raw map[string]json.RawMessage
+	if err := unmarshalJSONC(data, &raw); err != nil {
+		return nls
+	}
+	for key, value := range raw {
+		var s string
+		if json.Unmarshal(value, &s) == nil {
+			nls[key] = s
+			continue
+		}
+		// Newer extensions use {"message": ..., "comment": [...]}.
+		var msg struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(value, &msg) == nil && msg.Message != "" {
+			nls[key] = msg.Message
+		}
+	}
+	return nls
+}
+
+// localize resolves a "%key%" placeholder against nls, returning s
+// unchanged when it is not a placeholder or the key is unknown.
+func localize(s string, nls map[string]string) string {
+	if len(s) < 2 || !strings.HasPrefix(s, "%") || !strings.HasSuffix(s, "%") {
+		return s
+	}
+	if v, ok := nls[s[1:len(s)-1]]; ok {
+		return v
+	}
+	return s
+}
@@ -0,0 +1,107 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// windowsTerminalColorMap maps Windows Terminal scheme properties to
+// TerminalPalette fields. Windows Terminal calls magenta "purple".
+var windowsTerminalColorMap = map[string]string{
+	"black":               "black",
+	"red":                 "red",
+	"green":               "green",
+	"yellow":              "yellow",
+	"blue":                "blue",
+	"purple":              "magenta",
+	"cyan":                "cyan",
+	"white":               "white",
+	"brightBlack":         "bright_black",
+	"brightRed":           "bright_red",
+	"brightGreen":         "bright_green",
+	"brightYellow":        "bright_yellow",
+	"brightBlue":          "bright_blue",
+	"brightPurple":        "bright_magenta",
+	"brightCyan":          "bright_cyan",
+	"brightWhite":         "bright_white",
+	"background":          "background",
+	"foreground":          "foreground",
+	"cursorColor":         "cursor",
+	"selectionBackground": "selection",
+}
+
+// ErrMultipleSchemes is returned by ImportWindowsTerminal when the
+// settings define more than one color scheme.
+var ErrMultipleSchemes = errors.New("settings define several color schemes")
+
+// ImportWindowsTerminal parses a Windows Terminal color scheme into a
+// ThemeDef. data is either a single scheme object or a settings.json
+// (JSONC) file with exactly one entry in "schemes"; settings with more
+// fail with ErrMultipleSchemes, use ImportWindowsTerminalSchemes for
+// those.
+func ImportWindowsTerminal(data []byte) (*types.ThemeDef, error) {
+	themes, err := ImportWindowsTerminalSchemes(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(themes) > 1 {
+		return nil, fmt.Errorf("%w (%d); import them as a scheme set", ErrMultipleSchemes, len(themes))
+	}
+	return themes[0], nil
+}
+
+// ImportWindowsTerminalSchemes parses every color scheme in a Windows
+// Terminal settings.json, or the single scheme data holds, in order.
+func ImportWindowsTerminalSchemes(data []byte) ([]*types.ThemeDef, error) {
+	var settings struct {
+		Schemes []map[string]any `json:"schemes"`
+	}
+	if err := unmarshalJSONC(data, &settings); err != nil {
+		return nil, fmt.Errorf("invalid Windows Terminal JSON: %w", err)
+	}
+	schemes := settings.Schemes
+	if schemes == nil {
+		var scheme map[string]any
+		if err := unmarshalJSONC(data, &scheme); err != nil {
+			return nil, fmt.Errorf("invalid Windows Terminal JSON: %w", err)
+		}
+		schemes = []map[string]any{scheme}
+	}
+	if len(schemes) == 0 {
+		return nil, errors.New("no color schemes in Windows Terminal settings")
+	}
+
+	themes := make([]*types.ThemeDef, 0, len(schemes))
+	for i, scheme := range schemes {
+		name, _ := scheme["name"].(string)
+		if name == "" {
+			name = namelessFormats[FormatWindowsTerminal]
+		}
+		palette := &types.TerminalPalette{}
+		for key, field := range windowsTerminalColorMap {
+			if value, ok := scheme[key].(string); ok {
+				*palette.Field(field) = value
+			}
+		}
+		theme, err := terminalTheme(name, "windows-terminal", palette)
+		if err != nil {
+			return nil, fmt.Errorf("scheme %d (%s): %w", i, name, err)
+		}
+		themes = append(themes, theme)
+	}
+	return themes, nil
+}
+
+// isWindowsTerminal reports whether a decoded JSON object is a Windows
+// Terminal settings file or color scheme.
+func isWindowsTerminal(probe map[string]json.RawMessage) bool {
+	if _, ok := probe["schemes"]; ok {
+		return true
+	}
+	_, hasPurple := probe["purple"]
+	_, hasBrightBlack := probe["brightBlack"]
+	return hasPurple && hasBrightBlack
+}
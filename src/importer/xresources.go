@@ -0,0 +1,83 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// xresourcesColorMap maps X resource names, other than color0 to
+// color15, to TerminalPalette fields.
+var xresourcesColorMap = map[string]string{
+	"background":     "background",
+	"foreground":     "foreground",
+	"cursorColor":    "cursor",
+	"highlightColor": "selection",
+}
+
+// xresourcesPattern matches a color resource line such as
+// "*.background: #1d1f21" or "URxvt*color4: blue".
+var xresourcesPattern = regexp.MustCompile(`(?m)^\s*[\w.*-]*[*.](color[0-9]{1,2}|background|foreground|cursorColor)\s*:`)
+
+// ImportXresources parses the terminal colors of an .Xresources file into
+// a ThemeDef. Resources may be bound to any class or instance ("*",
+// "URxvt*", "XTerm.vt100."); the last binding of a name wins. Values may
+// refer to macros set with "#define". Other preprocessor directives are
+// ignored.
+func ImportXresources(data []byte) (*types.ThemeDef, error) {
+	palette := &types.TerminalPalette{}
+	defines := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "#define"); ok {
+			if fields := strings.Fields(rest); len(fields) >= 2 {
+				defines[fields[0]] = fields[1]
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "!") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		resource, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if v, ok := defines[value]; ok {
+			value = v
+		}
+		name := resource[strings.LastIndexAny(resource, "*.")+1:]
+		name = strings.TrimSpace(name)
+		if field, ok := xresourcesColorMap[name]; ok {
+			*palette.Field(field) = value
+			continue
+		}
+		if n, ok := strings.CutPrefix(name, "color"); ok {
+			if i, err := strconv.Atoi(n); err == nil && i >= 0 && i < 16 {
+				*palette.Field(types.TerminalColorNames[i]) = value
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid Xresources file: %w", err)
+	}
+
+	theme, err := terminalTheme(namelessFormats[FormatXresources], "xresources", palette)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Xresources colors: %w", err)
+	}
+	return theme, nil
+}
+
+// isXresources reports whether data looks like an .Xresources file with
+// terminal colors.
+func isXresources(data []byte) bool {
+	return xresourcesPattern.Match(data)
+}
@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// readYAML reads the subset of YAML used by terminal emulator configs:
+// block mappings nested by indentation, flow mappings and quoted or plain
+// scalars. Scalars are returned under their full dotted key like
+// readTOML. Sequences, anchors' targets and aliases are skipped; multi-
+// document streams are read as one. Errors are *SyntaxError values.
+func readYAML(data []byte) (map[string]string, error) {
+	type level struct {
+		indent int
+		key    string
+	}
+	out := make(map[string]string)
+	var stack []level
+	skipIndent := -1 // lines indented deeper than this belong to a skipped sequence
+
+	for i, raw := range strings.Split(string(bytes.TrimPrefix(data, utf8BOM)), "\n") {
+		line := strings.TrimRight(stripYAMLComment(raw), " \t\r")
+		content := strings.TrimLeft(line, " ")
+		if content == "" || content == "---" || content == "..." {
+			continue
+		}
+		if strings.HasPrefix(content, "\t") {
+			return nil, &SyntaxError{Line: i + 1, Column: 1, Msg: "tabs are not allowed in indentation"}
+		}
+		indent := len(line) - len(content)
+		if skipIndent >= 0 && indent > skipIndent {
+			continue
+		}
+		skipIndent = -1
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if content == "-" || strings.HasPrefix(content, "- ") {
+			skipIndent = indent
+			continue
+		}
+
+		key, value, ok := cutYAMLKey(content)
+		if !ok {
+			return nil, &SyntaxError{Line: i + 1, Column: indent + 1, Msg: "expected key: value"}
+		}
+		path := key
+		for j := len(stack) - 1; j >= 0; j-- {
+			path = stack[j].key + "." + path
+		}
+
+		// An anchor only names the value that follows it.
+		if strings.HasPrefix(value, "&") {
+			_, value, _ = strings.Cut(value, " ")
+			value = strings.TrimSpace(value)
+		}
+		switch {
+		case value == "":
+			stack = append(stack, level{indent: indent, key: key})
+		case strings.HasPrefix(value, "*"), strings.HasPrefix(value, "["),
+			strings.HasPrefix(value, "|"), strings.HasPrefix(value, ">"):
+			// Aliases, flow sequences and block scalars carry no colors.
+			skipIndent = indent
+		case strings.HasPrefix(value, "{"):
+			if err := yamlFlowMapping(out, path, value); err != nil {
+				return nil, &SyntaxError{Line: i + 1, Column: indent + 1, Msg: err.Error()}
+			}
+		default:
+			s, err := unquoteScalar(value)
+			if err != nil {
+				return nil, &SyntaxError{Line: i + 1, Column: indent + 1, Msg: fmt.Sprintf("invalid value for %s: %v", path, err)}
+			}
+			out[path] = s
+		}
+	}
+	return out, nil
+}
+
+// cutYAMLKey splits a "key: value" line at the first colon outside
+// quotes that ends the line or is followed by a space.
+func cutYAMLKey(content string) (key, value string, ok bool) {
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		switch c := content[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ':' && (i+1 == len(content) || content[i+1] == ' '):
+			k, err := unquoteScalar(content[:i])
+			if err != nil || k == "" {
+				return "", "", false
+			}
+			return k, strings.TrimSpace(content[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// yamlFlowMapping stores the scalars of a "{ key: value, ... }" mapping
+// under prefix.
+func yamlFlowMapping(out map[string]string, prefix, value string) error {
+	if !strings.HasSuffix(value, "}") {
+		return fmt.Errorf("unterminated flow mapping %s", prefix)
+	}
+	for _, member := range splitTopLevel(value[1:len(value)-1], ',') {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+		k, v, ok := cutYAMLKey(member)
+		if !ok {
+			return fmt.Errorf("expected key: value in flow mapping %s", prefix)
+		}
+		path := joinKey(prefix, k)
+		switch {
+		case strings.HasPrefix(v, "{"):
+			if err := yamlFlowMapping(out, path, v); err != nil {
+				return err
+			}
+		case strings.HasPrefix(v, "["):
+		default:
+			s, err := unquoteScalar(v)
+			if err != nil {
+				return fmt.Errorf("invalid value for %s: %w", path, err)
+			}
+			out[path] = s
+		}
+	}
+	return nil
+}
+
+// stripYAMLComment removes a comment: a "#" outside quotes that starts
+// the line or follows whitespace. Quotes only open a string at the start
+// of a scalar, so apostrophes in plain scalars are not mistaken for one.
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t:{[,", line[i-1]) >= 0):
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/orchestra-mcp/themes/src/builtin"
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// deriveRule computes a missing key from colors the theme already has.
+// derive returns false when its inputs are absent or unparseable.
+type deriveRule struct {
+	key    string
+	derive func(c palette) (color.Color, bool)
+}
+
+// palette gives rules typed access to a theme's colors.
+type palette struct {
+	colors map[string]string
+	light  bool
+}
+
+func (p palette) get(key string) (color.Color, bool) {
+	v, ok := p.colors[key]
+	if !ok {
+		return color.Color{}, false
+	}
+	c, err := color.Parse(v)
+	return c, err == nil
+}
+
+// first returns the first of keys that is present and parseable.
+func (p palette) first(keys ...string) (color.Color, bool) {
+	for _, k := range keys {
+		if c, ok := p.get(k); ok {
+			return c, true
+		}
+	}
+	return color.Color{}, false
+}
+
+// shift moves a color away from the background: darker on light themes,
+// lighter on dark ones.
+func (p palette) shift(c color.Color, amount float64) color.Color {
+	if p.light {
+		return c.Lighten(-amount)
+	}
+	return c.Lighten(amount)
+}
+
+func copyOf(keys ...string) func(p palette) (color.Color, bool) {
+	return func(p palette) (color.Color, bool) { return p.first(keys...) }
+}
+
+func shifted(amount float64, keys ...string) func(p palette) (color.Color, bool) {
+	return func(p palette) (color.Color, bool) {
+		c, ok := p.first(keys...)
+		if !ok {
+			return c, false
+		}
+		return p.shift(c, amount), true
+	}
+}
+
+// onColor picks whichever of the theme's background and foreground reads
+// better on top of the first available key.
+func onColor(keys ...string) func(p palette) (color.Color, bool) {
+	return func(p palette) (color.Color, bool) {
+		base, ok := p.first(keys...)
+		if !ok {
+			return base, false
+		}
+		bg, okBg := p.first(colorkeys.Background, colorkeys.EditorBackground)
+		fg, okFg := p.first(colorkeys.Foreground, colorkeys.EditorForeground)
+		if !okBg || !okFg {
+			return color.Color{}, false
+		}
+		if base.IsLight() == bg.IsLight() {
+			return fg, true
+		}
+		return bg, true
+	}
+}
+
+// deriveRules are tried in order, repeatedly, until no rule applies, so
+// later keys can build on earlier derived ones.
+var deriveRules = []deriveRule{
+	{colorkeys.Background, copyOf(colorkeys.EditorBackground)},
+	{colorkeys.Foreground, copyOf(colorkeys.EditorForeground)},
+	{colorkeys.EditorBackground, copyOf(colorkeys.Background)},
+	{colorkeys.EditorForeground, copyOf(colorkeys.Foreground)},
+	{colorkeys.Primary, copyOf(colorkeys.ButtonBackground, colorkeys.FocusBorder, colorkeys.Accent)},
+	{colorkeys.Accent, copyOf(colorkeys.Primary)},
+	{colorkeys.Secondary, func(p palette) (color.Color, bool) {
+		fg, ok1 := p.first(colorkeys.Foreground, colorkeys.EditorForeground)
+		bg, ok2 := p.first(colorkeys.Background, colorkeys.EditorBackground)
+		return color.Mix(fg, bg, 0.35), ok1 && ok2
+	}},
+	{colorkeys.Border, shifted(0.10, colorkeys.Background, colorkeys.EditorBackground)},
+	{colorkeys.FocusBorder, copyOf(colorkeys.Primary)},
+	{colorkeys.SidebarBackground, shifted(-0.03, colorkeys.Background, colorkeys.EditorBackground)},
+	{colorkeys.SidebarForeground, copyOf(colorkeys.Foreground, colorkeys.EditorForeground)},
+	{colorkeys.ActivityBarBackground, shifted(-0.05, colorkeys.Background, colorkeys.EditorBackground)},
+	{colorkeys.ActivityBarForeground, copyOf(colorkeys.Secondary)},
+	{colorkeys.TitleBarBackground, copyOf(colorkeys.ActivityBarBackground)},
+	{colorkeys.TitleBarForeground, copyOf(colorkeys.Foreground, colorkeys.EditorForeground)},
+	{colorkeys.StatusBarBackground, copyOf(colorkeys.Primary)},
+	{colorkeys.StatusBarForeground, onColor(colorkeys.StatusBarBackground)},
+	{colorkeys.EditorCursor, copyOf(colorkeys.EditorForeground, colorkeys.Foreground)},
+	{colorkeys.EditorSelection, shifted(0.15, colorkeys.EditorBackground, colorkeys.Background)},
+	{colorkeys.EditorLineHighlight, shifted(0.04, colorkeys.EditorBackground, colorkeys.Background)},
+	{colorkeys.ListSelection, copyOf(colorkeys.EditorSelection)},
+	{colorkeys.InputBackground, shifted(0.05, colorkeys.Background, colorkeys.EditorBackground)},
+	{colorkeys.InputForeground, copyOf(colorkeys.Foreground, colorkeys.EditorForeground)},
+	{colorkeys.InputBorder, copyOf(colorkeys.Border)},
+	{colorkeys.ButtonBackground, copyOf(colorkeys.Primary)},
+	{colorkeys.ButtonForeground, onColor(colorkeys.ButtonBackground)},
+}
+
+// complete returns theme with every canonical color key and every
+// terminal palette color present. Missing keys are derived from the
+// theme's own colors where possible and otherwise taken from the built-in
+// theme of the same type; see completeTerminal for the palette. The names
+// of filled-in keys are listed in Synthesized, palette fields as
+// "terminal.<field>". Complete themes are returned unchanged; otherwise a
+// copy is made.
+func complete(theme *types.ThemeDef) *types.ThemeDef {
+	missing := !terminalComplete(theme.Terminal)
+	for _, key := range colorkeys.Names() {
+		if _, ok := theme.Colors[key]; !ok {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return theme
+	}
+
+	out := *theme
+	out.Colors = make(map[string]string, len(theme.Colors)+len(colorkeys.Names()))
+	for k, v := range theme.Colors {
+		out.Colors[k] = v
+	}
+
+	p := palette{colors: out.Colors, light: theme.Type == "light"}
+	if theme.Type == "" {
+		if bg, ok := p.first(colorkeys.Background, colorkeys.EditorBackground); ok {
+			p.light = bg.IsLight()
+		}
+	}
+
+	var synthesized []string
+	for progress := true; progress; {
+		progress = false
+		for _, rule := range deriveRules {
+			if _, ok := out.Colors[rule.key]; ok {
+				continue
+			}
+			if c, ok := rule.derive(p); ok {
+				out.Colors[rule.key] = c.Hex()
+				synthesized = append(synthesized, rule.key)
+				progress = true
+			}
+		}
+	}
+
+	fallback := builtin.DarkTheme()
+	if p.light {
+		fallback = builtin.LightTheme()
+	}
+	for _, key := range colorkeys.Names() {
+		if _, ok := out.Colors[key]; !ok {
+			out.Colors[key] = fallback.Colors[key]
+			synthesized = append(synthesized, key)
+		}
+	}
+
+	terminal, filled := completeTerminal(&out, p)
+	out.Terminal = terminal
+	synthesized = append(synthesized, filled...)
+
+	sort.Strings(synthesized)
+	out.Synthesized = synthesized
+	return &out
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/orchestra-mcp/themes/src/contrast"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// ContrastReport audits the resolved, completed form of a theme for
+// WCAG contrast.
+func (s *ThemesService) ContrastReport(id string, opts contrast.Options) (*contrast.Report, error) {
+	theme, err := s.GetTheme(id)
+	if err != nil {
+		return nil, err
+	}
+	report := contrast.Audit(theme, opts)
+	return &report, nil
+}
+
+// RepairOptions controls RepairContrast.
+type RepairOptions struct {
+	// Level is the target conformance level, AA (default) or AAA.
+	Level string
+	// NewID, when set, registers the repaired colors as a new theme that
+	// extends the source theme; it must not be registered already. When
+	// NewID is the source theme's ID, the patch is applied to the source
+	// theme instead. Otherwise nothing is stored and only the patch is
+	// returned.
+	NewID string
+	// Name is the display name of the new theme; it defaults to the
+	// source name with the level appended.
+	Name string
+}
+
+// RepairResult describes the changes RepairContrast made.
+type RepairResult struct {
+	ThemeID string         `json:"theme_id"`
+	Level   string         `json:"level"`
+	Fixes   []contrast.Fix `json:"fixes"`
+	// Patch is a JSON merge patch that applies the fixes to the source
+	// theme through PatchTheme. It is nil when nothing needed fixing.
+	Patch map[string]any `json:"patch,omitempty"`
+	// Theme is the derived theme, when one was registered, or the source
+	// theme when it was repaired in place.
+	Theme *types.ThemeDef `json:"theme,omitempty"`
+}
+
+// RepairContrast adjusts the foreground colors of a theme that fail the
+// requested contrast level. Keys the source theme only had synthesized
+// are pinned in the patch and the derived theme, so completion cannot
+// re-derive them from a repaired color.
+func (s *ThemesService) RepairContrast(id string, opts RepairOptions) (*RepairResult, error) {
+	level, err := contrast.ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	raw, ok := s.themes[id]
+	var view *types.ThemeDef
+	if ok {
+		view = s.view(raw)
+	}
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrThemeNotFound, id)
+	}
+
+	repaired, fixes := contrast.Repair(view, level)
+	result := &RepairResult{ThemeID: id, Level: level, Fixes: fixes}
+
+	colors := make(map[string]string)
+	for _, key := range view.Synthesized {
+		if value, ok := view.Colors[key]; ok {
+			colors[key] = value
+		}
+	}
+	changed := false
+	for key, value := range repaired.Colors {
+		if value != view.Colors[key] {
+			colors[key] = value
+			changed = true
+		}
+	}
+
+	// Token rules inherited from base themes come first in the view;
+	// the source theme's own rules follow at offset.
+	offset := len(view.TokenColors) - len(raw.TokenColors)
+	own := make([]types.TokenColor, len(raw.TokenColors))
+	for i, tc := range raw.TokenColors {
+		own[i] = copyTokenColor(tc)
+	}
+	var overrides, baseOverrides []types.TokenColor
+	for i, tc := range repaired.TokenColors {
+		fg := tc.Settings["foreground"]
+		if fg == view.TokenColors[i].Settings["foreground"] {
+			continue
+		}
+		changed = true
+		overrides = append(overrides, copyTokenColor(tc))
+		if i >= offset {
+			own[i-offset].Settings["foreground"] = fg
+		} else {
+			// Rules from base themes cannot be edited in place; later
+			// rules win, so a repaired copy is appended instead.
+			baseOverrides = append(baseOverrides, copyTokenColor(tc))
+		}
+	}
+
+	if changed {
+		result.Patch = map[string]any{"colors": colors}
+		if len(overrides) > 0 {
+			result.Patch["token_colors"] = append(own, baseOverrides...)
+		}
+	}
+
+	if opts.NewID == "" {
+		return result, nil
+	}
+	if opts.NewID == id {
+		return s.repairInPlace(result)
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = fmt.Sprintf("%s (%s)", view.Name, level)
+	}
+	derived := &types.ThemeDef{
+		ID:          opts.NewID,
+		Name:        name,
+		Description: view.Description,
+		Author:      view.Author,
+		Type:        view.Type,
+		Extends:     id,
+		Colors:      colors,
+		TokenColors: overrides,
+	}
+	if err := s.register(derived, false); err != nil {
+		return nil, err
+	}
+	s.logger.Info().Str("theme", derived.ID).Str("base", id).Int("fixes", len(fixes)).Msg("contrast repaired")
+	result.Theme, err = s.GetTheme(derived.ID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// repairInPlace applies the patch of result to the repaired theme.
+func (s *ThemesService) repairInPlace(result *RepairResult) (*RepairResult, error) {
+	if result.Patch == nil {
+		theme, err := s.GetTheme(result.ThemeID)
+		if err != nil {
+			return nil, err
+		}
+		result.Theme = theme
+		return result, nil
+	}
+	data, err := json.Marshal(result.Patch)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.PatchTheme(result.ThemeID, data); err != nil {
+		return nil, err
+	}
+	s.logger.Info().Str("theme", result.ThemeID).Int("fixes", len(result.Fixes)).Msg("contrast repaired in place")
+	result.Theme, err = s.GetTheme(result.ThemeID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
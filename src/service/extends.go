@@ -0,0 +1,201 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// resolve returns theme with its "extends" chain merged in, base first.
+// The result is always a fresh copy, so callers can never mutate a
+// stored definition through it. Callers must hold s.mu.
+func (s *ThemesService) resolve(theme *types.ThemeDef) (*types.ThemeDef, error) {
+	if theme == nil {
+		return nil, nil
+	}
+	if theme.Extends == "" {
+		return cloneTheme(theme), nil
+	}
+
+	chain, err := s.extendsChain(theme, s.themes)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := &types.ThemeDef{Colors: make(map[string]string)}
+	for i := len(chain) - 1; i >= 0; i-- {
+		mergeTheme(resolved, chain[i])
+	}
+	resolved.ID = theme.ID
+	resolved.Name = theme.Name
+	resolved.Extends = theme.Extends
+	return resolved, nil
+}
+
+// resolveOrRaw resolves theme, falling back to a copy of the raw
+// definition (and logging why) when its chain is broken, e.g. after a
+// base was deleted. Callers must hold s.mu.
+func (s *ThemesService) resolveOrRaw(theme *types.ThemeDef) *types.ThemeDef {
+	resolved, err := s.resolve(theme)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("theme", theme.ID).Msg("failed to resolve theme inheritance")
+		return cloneTheme(theme)
+	}
+	return resolved
+}
+
+// view returns the theme as clients see it: resolved and completed. Like
+// resolve, it never shares state with the stored definition. Callers
+// must hold s.mu.
+func (s *ThemesService) view(theme *types.ThemeDef) *types.ThemeDef {
+	return complete(s.resolveOrRaw(theme))
+}
+
+// extendsChain returns theme followed by each of its ancestors, looked up
+// in themes. It fails on cycles and on missing base themes.
+func (s *ThemesService) extendsChain(theme *types.ThemeDef, themes map[string]*types.ThemeDef) ([]*types.ThemeDef, error) {
+	chain := []*types.ThemeDef{theme}
+	seen := map[string]bool{theme.ID: true}
+	path := []string{theme.ID}
+
+	for cur := theme; cur.Extends != ""; {
+		path = append(path, cur.Extends)
+		if seen[cur.Extends] {
+			return nil, fmt.Errorf("%w: cycle %s", ErrInvalidExtends, strings.Join(path, " -> "))
+		}
+		base, ok := themes[cur.Extends]
+		if !ok {
+			return nil, fmt.Errorf("%w: base theme %q of %q not found", ErrInvalidExtends, cur.Extends, cur.ID)
+		}
+		seen[base.ID] = true
+		chain = append(chain, base)
+		cur = base
+	}
+	return chain, nil
+}
+
+// checkExtends verifies that storing theme keeps every chain it is part
+// of acyclic and complete. Callers must hold s.mu.
+func (s *ThemesService) checkExtends(theme *types.ThemeDef) error {
+	if theme.Extends == "" {
+		return nil
+	}
+	candidate := make(map[string]*types.ThemeDef, len(s.themes)+1)
+	for id, t := range s.themes {
+		candidate[id] = t
+	}
+	candidate[theme.ID] = theme
+	_, err := s.extendsChain(theme, candidate)
+	return err
+}
+
+// inheritsFrom reports whether the theme id is baseID or extends it,
+// directly or through intermediate themes. Callers must hold s.mu.
+func (s *ThemesService) inheritsFrom(id, baseID string) bool {
+	seen := make(map[string]bool)
+	for id != "" && !seen[id] {
+		if id == baseID {
+			return true
+		}
+		seen[id] = true
+		t, ok := s.themes[id]
+		if !ok {
+			return false
+		}
+		id = t.Extends
+	}
+	return false
+}
+
+// mergeTheme overlays src onto dst: non-empty scalar fields, a set
+// SemanticHighlighting, color keys and terminal palette colors replace
+// dst's, and token color and semantic token rules are appended so that
+// rules from the derived theme take precedence over the base.
+func mergeTheme(dst, src *types.ThemeDef) {
+	if src.Description != "" {
+		dst.Description = src.Description
+	}
+	if src.Author != "" {
+		dst.Author = src.Author
+	}
+	if src.Type != "" {
+		dst.Type = src.Type
+	}
+	if src.Source != "" {
+		dst.Source = src.Source
+	}
+	for key, value := range src.Colors {
+		dst.Colors[key] = value
+	}
+	for _, tc := range src.TokenColors {
+		dst.TokenColors = append(dst.TokenColors, copyTokenColor(tc))
+	}
+	if src.SemanticHighlighting != nil {
+		enabled := *src.SemanticHighlighting
+		dst.SemanticHighlighting = &enabled
+	}
+	for _, sc := range src.SemanticTokenColors {
+		dst.SemanticTokenColors = append(dst.SemanticTokenColors, copySemanticTokenColor(sc))
+	}
+	if src.Terminal != nil {
+		if dst.Terminal == nil {
+			dst.Terminal = &types.TerminalPalette{}
+		}
+		for _, name := range types.TerminalColorNames {
+			if v := *src.Terminal.Field(name); v != "" {
+				*dst.Terminal.Field(name) = v
+			}
+		}
+	}
+}
+
+// cloneTheme returns a deep copy of theme.
+func cloneTheme(theme *types.ThemeDef) *types.ThemeDef {
+	out := *theme
+	out.Colors = make(map[string]string, len(theme.Colors))
+	for k, v := range theme.Colors {
+		out.Colors[k] = v
+	}
+	out.TokenColors = nil
+	for _, tc := range theme.TokenColors {
+		out.TokenColors = append(out.TokenColors, copyTokenColor(tc))
+	}
+	out.SemanticTokenColors = nil
+	for _, sc := range theme.SemanticTokenColors {
+		out.SemanticTokenColors = append(out.SemanticTokenColors, copySemanticTokenColor(sc))
+	}
+	if theme.SemanticHighlighting != nil {
+		enabled := *theme.SemanticHighlighting
+		out.SemanticHighlighting = &enabled
+	}
+	if theme.Terminal != nil {
+		terminal := *theme.Terminal
+		out.Terminal = &terminal
+	}
+	out.Synthesized = append([]string(nil), theme.Synthesized...)
+	return &out
+}
+
+func copyTokenColor(tc types.TokenColor) types.TokenColor {
+	out := types.TokenColor{
+		Name:     tc.Name,
+		Scope:    append([]string(nil), tc.Scope...),
+		Settings: make(map[string]string, len(tc.Settings)),
+	}
+	for k, v := range tc.Settings {
+		out.Settings[k] = v
+	}
+	return out
+}
+
+func copySemanticTokenColor(sc types.SemanticTokenColor) types.SemanticTokenColor {
+	sc.Modifiers = append([]string(nil), sc.Modifiers...)
+	for _, flag := range []**bool{&sc.Style.Bold, &sc.Style.Italic, &sc.Style.Underline, &sc.Style.Strikethrough} {
+		if *flag != nil {
+			v := **flag
+			*flag = &v
+		}
+	}
+	return sc
+}
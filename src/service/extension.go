@@ -0,0 +1,301 @@
+package service
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// ExtensionImport reports the themes registered from a VS Code extension.
+type ExtensionImport struct {
+	Extension   string                 `json:"extension"`
+	DisplayName string                 `json:"display_name,omitempty"`
+	Version     string                 `json:"version,omitempty"`
+	Themes      []ExtensionThemeImport `json:"themes"`
+	// Imported is the number of themes registered successfully.
+	Imported int `json:"imported"`
+	// Skipped is the number of themes not registered because their ID
+	// is taken.
+	Skipped int `json:"skipped"`
+}
+
+// ExtensionThemeImport is the outcome for one contributed theme. Skipped
+// explains why a theme that imported was not registered.
+type ExtensionThemeImport struct {
+	Label   string          `json:"label"`
+	Path    string          `json:"path"`
+	ID      string          `json:"id,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Skipped string          `json:"skipped,omitempty"`
+	Theme   *types.ThemeDef `json:"theme,omitempty"`
+}
+
+// ImportVSIX imports every theme contributed by a .vsix package and
+// registers those that import and validate. Themes whose ID is already
+// registered are skipped unless opts.Overwrite is set, as in
+// DiscoverVSCodeThemes. Failures of individual themes are reported in
+// the result rather than as an error.
+func (s *ThemesService) ImportVSIX(data []byte, opts DiscoverOptions) (*ExtensionImport, error) {
+	ext, err := importer.ImportVSIX(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.registerExtension(ext, opts), nil
+}
+
+// ImportSublimePackage imports every color scheme of a .sublime-package
+// archive and registers those that import and validate. name identifies
+// the package in the result and log. Clashing IDs are handled as in
+// ImportVSIX. Failures of individual schemes are reported in the result
+// rather than as an error.
+func (s *ThemesService) ImportSublimePackage(name string, data []byte, opts DiscoverOptions) (*ExtensionImport, error) {
+	themes, err := importer.ImportSublimePackage(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.registerExtension(&importer.Extension{Name: name, Themes: themes}, opts), nil
+}
+
+// ImportWindowsTerminalSchemes imports every color scheme of a Windows
+// Terminal settings.json and registers those that validate. name
+// identifies the settings in the result and log. Clashing IDs are
+// handled as in ImportVSIX.
+func (s *ThemesService) ImportWindowsTerminalSchemes(name string, data []byte, opts DiscoverOptions) (*ExtensionImport, error) {
+	schemes, err := importer.ImportWindowsTerminalSchemes(data)
+	if err != nil {
+		return nil, err
+	}
+	themes := make([]importer.ExtensionTheme, len(schemes))
+	for i, theme := range schemes {
+		themes[i] = importer.ExtensionTheme{
+			Label: theme.Name,
+			Path:  fmt.Sprintf("schemes[%d]", i),
+			Theme: theme,
+		}
+	}
+	return s.registerExtension(&importer.Extension{Name: name, Themes: themes}, opts), nil
+}
+
+// registerExtension registers the imported themes of ext, skipping those
+// whose ID clashes as registerSkip describes.
+func (s *ThemesService) registerExtension(ext *importer.Extension, opts DiscoverOptions) *ExtensionImport {
+	result := &ExtensionImport{
+		Extension:   ext.Name,
+		DisplayName: ext.DisplayName,
+		Version:     ext.Version,
+	}
+	if ext.Publisher != "" {
+		result.Extension = ext.Publisher + "." + ext.Name
+	}
+	for _, et := range ext.Themes {
+		item := ExtensionThemeImport{Label: et.Label, Path: et.Path}
+		err := et.Err
+		if err == nil {
+			item.ID = et.Theme.ID
+			err = s.register(et.Theme, opts.Overwrite)
+			if reason := registerSkip(err); reason != "" {
+				item.Skipped = reason
+				result.Skipped++
+				result.Themes = append(result.Themes, item)
+				continue
+			}
+		}
+		if err == nil {
+			item.Theme, err = s.GetTheme(et.Theme.ID)
+		}
+		if err != nil {
+			item.Error = err.Error()
+		} else {
+			result.Imported++
+		}
+		result.Themes = append(result.Themes, item)
+	}
+	s.logger.Info().Str("extension", result.Extension).Int("themes", result.Imported).
+		Int("skipped", result.Skipped).Msg("extension imported")
+	return result
+}
+
+// DiscoverOptions controls DiscoverVSCodeThemes and the package imports
+// ImportVSIX, ImportSublimePackage and ImportWindowsTerminalSchemes.
+type DiscoverOptions struct {
+	// Overwrite replaces user themes that already have a discovered or
+	// imported theme's ID. Otherwise such themes are skipped.
+	Overwrite bool
+}
+
+// DiscoveryResult reports the outcome of scanning an extensions directory.
+type DiscoveryResult struct {
+	Dir     string            `json:"dir"`
+	Added   []DiscoveredTheme `json:"added"`
+	Skipped []DiscoveredTheme `json:"skipped"`
+	Failed  []DiscoveredTheme `json:"failed"`
+}
+
+// DiscoveredTheme is one theme found while scanning. Extension is the
+// extension directory name; Reason explains a skip or failure.
+type DiscoveredTheme struct {
+	Extension string `json:"extension"`
+	Label     string `json:"label,omitempty"`
+	Path      string `json:"path,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// DiscoverVSCodeThemes scans a VS Code extensions directory, such as
+// ~/.vscode/extensions, and registers the themes contributed by each
+// installed extension. Extensions without themes are ignored, as are the
+// directories VS Code lists in its .obsolete file. When several versions
+// of an extension are installed, only the highest is imported and the
+// others are reported as skipped. Themes whose ID is already registered
+// are skipped unless opts.Overwrite is set; built-in themes are never
+// replaced.
+func (s *ThemesService) DiscoverVSCodeThemes(dir string, opts DiscoverOptions) (*DiscoveryResult, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read extensions directory: %w", err)
+	}
+	obsolete := readObsolete(filepath.Join(dir, ".obsolete"))
+
+	result := &DiscoveryResult{
+		Dir:     dir,
+		Added:   []DiscoveredTheme{},
+		Skipped: []DiscoveredTheme{},
+		Failed:  []DiscoveredTheme{},
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() && !strings.HasPrefix(name, ".") && !obsolete[name] {
+			names = append(names, name)
+		}
+	}
+	names, superseded := latestExtensions(names)
+	for _, name := range names {
+		ext, err := importer.ImportExtensionDir(filepath.Join(dir, name))
+		if errors.Is(err, importer.ErrInvalidExtension) {
+			continue
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, DiscoveredTheme{Extension: name, Reason: err.Error()})
+			continue
+		}
+		for _, et := range ext.Themes {
+			item := DiscoveredTheme{Extension: name, Label: et.Label, Path: et.Path}
+			if et.Err != nil {
+				item.Reason = et.Err.Error()
+				result.Failed = append(result.Failed, item)
+				continue
+			}
+			item.ID = et.Theme.ID
+			err := s.register(et.Theme, opts.Overwrite)
+			if reason := registerSkip(err); reason != "" {
+				item.Reason = reason
+				result.Skipped = append(result.Skipped, item)
+				continue
+			}
+			if err != nil {
+				item.Reason = err.Error()
+				result.Failed = append(result.Failed, item)
+				continue
+			}
+			result.Added = append(result.Added, item)
+		}
+	}
+
+	for _, name := range superseded {
+		result.Skipped = append(result.Skipped, DiscoveredTheme{
+			Extension: name,
+			Reason:    "a newer version of the extension is installed",
+		})
+	}
+
+	s.logger.Info().Str("dir", dir).Int("added", len(result.Added)).
+		Int("skipped", len(result.Skipped)).Int("failed", len(result.Failed)).
+		Msg("vscode themes discovered")
+	return result, nil
+}
+
+// registerSkip returns why register refused a theme because its ID
+// clashes with an existing theme, or "" for any other outcome.
+func registerSkip(err error) string {
+	switch {
+	case errors.Is(err, ErrBuiltinTheme):
+		return "built-in theme with the same ID"
+	case errors.Is(err, ErrDirTheme):
+		return "themes directory theme with the same ID"
+	case errors.Is(err, ErrThemeExists):
+		return "already registered"
+	}
+	return ""
+}
+
+// extensionDirPattern splits an installed extension directory name,
+// "publisher.name-1.2.3" with an optional target platform suffix such as
+// "-darwin-arm64", into the extension ID and version.
+var extensionDirPattern = regexp.MustCompile(`^(.+)-(\d+(?:\.\d+)*)(?:-[a-z0-9]+(?:-[a-z0-9]+)*)?$`)
+
+// latestExtensions returns the directory names that hold the highest
+// installed version of each extension, in their original order, and the
+// names of the older versions. Names without a version are kept.
+func latestExtensions(names []string) (latest, superseded []string) {
+	best := make(map[string]string)
+	for _, name := range names {
+		m := extensionDirPattern.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		id := strings.ToLower(m[1])
+		if cur, ok := best[id]; !ok || compareVersions(m[2], extensionDirPattern.FindStringSubmatch(cur)[2]) > 0 {
+			best[id] = name
+		}
+	}
+	for _, name := range names {
+		m := extensionDirPattern.FindStringSubmatch(name)
+		if m == nil || best[strings.ToLower(m[1])] == name {
+			latest = append(latest, name)
+		} else {
+			superseded = append(superseded, name)
+		}
+	}
+	return latest, superseded
+}
+
+// compareVersions compares dotted numeric versions component by
+// component, treating missing components as zero.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return cmp.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+// readObsolete reads the set of extension directories VS Code has marked
+// for removal. A missing or unreadable file yields an empty set.
+func readObsolete(name string) map[string]bool {
+	obsolete := make(map[string]bool)
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return obsolete
+	}
+	_ = json.Unmarshal(data, &obsolete)
+	return obsolete
+}
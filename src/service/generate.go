@@ -0,0 +1,22 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/orchestra-mcp/themes/src/generator"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// GenerateTheme builds a complete theme from seed colors and registers
+// it as a user theme. It fails with ErrThemeExists rather than replace
+// a theme with the same ID.
+func (s *ThemesService) GenerateTheme(seeds generator.Seeds) (*types.ThemeDef, error) {
+	theme, err := generator.Generate(seeds)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTheme, err)
+	}
+	if err := s.register(theme, false); err != nil {
+		return nil, err
+	}
+	return s.GetTheme(theme.ID)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// tokenColorSettings are the token rule settings that hold colors.
+var tokenColorSettings = []string{"foreground", "background"}
+
+// normalizeTheme rewrites every color value in theme to canonical form
+// and fills in the parsed fields of semantic token selectors. Values that
+// do not parse are left untouched; validation rejects them before a theme
+// is stored. Preserved "raw." keys are kept verbatim.
+func normalizeTheme(theme *types.ThemeDef) {
+	for key, value := range theme.Colors {
+		if strings.HasPrefix(key, colorkeys.RawPrefix) {
+			continue
+		}
+		if normalized, err := color.Normalize(value); err == nil {
+			theme.Colors[key] = normalized
+		}
+	}
+
+	for _, tc := range theme.TokenColors {
+		for _, key := range tokenColorSettings {
+			value, ok := tc.Settings[key]
+			if !ok {
+				continue
+			}
+			if normalized, err := color.Normalize(value); err == nil {
+				tc.Settings[key] = normalized
+			}
+		}
+	}
+
+	for i := range theme.SemanticTokenColors {
+		style := &theme.SemanticTokenColors[i].Style
+		if normalized, err := color.Normalize(style.Foreground); err == nil {
+			style.Foreground = normalized
+		}
+	}
+	importer.FillSemanticSelectors(theme.SemanticTokenColors)
+
+	if theme.Terminal != nil {
+		for _, name := range types.TerminalColorNames {
+			field := theme.Terminal.Field(name)
+			if normalized, err := color.Normalize(*field); err == nil && *field != "" {
+				*field = normalized
+			}
+		}
+	}
+}
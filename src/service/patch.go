@@ -0,0 +1,53 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// applyMergePatch returns a copy of theme with an RFC 7396 merge patch
+// applied. The theme is round-tripped through its JSON form so patch keys
+// match the field names clients see ("colors", "token_colors", ...).
+func applyMergePatch(theme *types.ThemeDef, patch any) (*types.ThemeDef, error) {
+	data, err := json.Marshal(theme)
+	if err != nil {
+		return nil, fmt.Errorf("marshal theme: %w", err)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal theme: %w", err)
+	}
+
+	merged, err := json.Marshal(mergePatch(doc, patch))
+	if err != nil {
+		return nil, fmt.Errorf("marshal patched theme: %w", err)
+	}
+	var result types.ThemeDef
+	if err := json.Unmarshal(merged, &result); err != nil {
+		return nil, fmt.Errorf("invalid patched theme: %w", err)
+	}
+	return &result, nil
+}
+
+// mergePatch implements the MergePatch algorithm from RFC 7396.
+func mergePatch(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = make(map[string]any)
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
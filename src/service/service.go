@@ -2,49 +2,284 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"sync"
 
 	"github.com/orchestra-mcp/themes/src/builtin"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/exporter"
 	"github.com/orchestra-mcp/themes/src/types"
 	"github.com/rs/zerolog"
 )
 
+var (
+	// ErrThemeNotFound is returned when a theme ID is not registered.
+	ErrThemeNotFound = errors.New("theme not found")
+	// ErrBuiltinTheme is returned when a write targets a built-in theme.
+	ErrBuiltinTheme = errors.New("built-in themes are read-only")
+	// ErrInvalidExtends is returned when a theme's "extends" chain is
+	// cyclic or references a missing base theme.
+	ErrInvalidExtends = errors.New("invalid theme inheritance")
+	// ErrInvalidTheme is returned when a theme fails schema validation.
+	// The wrapped *validator.Error carries the diagnostics.
+	ErrInvalidTheme = errors.New("invalid theme")
+	// ErrThemeExists is returned when a new theme would replace one that
+	// is already registered.
+	ErrThemeExists = errors.New("theme already exists")
+)
+
 // ThemesService manages theme registration, activation, and persistence.
 type ThemesService struct {
 	mu          sync.RWMutex
 	themes      map[string]*types.ThemeDef
+	builtins    map[string]bool
+	dirThemes   map[string]string // theme ID -> file, for themes directory themes
 	activeID    string
+	defaultID   string
 	storagePath string
 	listeners   []func(old, new *types.ThemeDef)
 	logger      zerolog.Logger
+
+	// pendingActive is a stored active theme preference that names a
+	// theme not loaded yet, such as one from the themes directory.
+	pendingActive string
 }
 
-// New creates a ThemesService with built-in themes loaded.
+// New creates a ThemesService with built-in and stored user themes loaded.
 func New(storagePath, defaultTheme string, logger zerolog.Logger) *ThemesService {
 	svc := &ThemesService{
 		themes:      make(map[string]*types.ThemeDef),
+		builtins:    make(map[string]bool),
+		dirThemes:   make(map[string]string),
 		activeID:    defaultTheme,
+		defaultID:   defaultTheme,
 		storagePath: storagePath,
 		logger:      logger,
 	}
 
 	for _, t := range builtin.BuiltinThemes() {
 		svc.themes[t.ID] = t
+		svc.builtins[t.ID] = true
 	}
 
+	svc.loadUserThemes()
 	svc.loadPreference()
 	return svc
 }
 
-// RegisterTheme registers a theme definition.
-func (s *ThemesService) RegisterTheme(theme *types.ThemeDef) {
+// RegisterTheme registers a theme definition and persists it to the
+// user theme store. Built-in theme IDs cannot be overwritten. Listeners
+// are notified when the active theme, or a theme it inherits from, is
+// replaced.
+func (s *ThemesService) RegisterTheme(theme *types.ThemeDef) error {
+	return s.register(theme, true)
+}
+
+// register stores theme, replacing a user theme with the same ID only if
+// replace is set; otherwise it fails with ErrThemeExists.
+func (s *ThemesService) register(theme *types.ThemeDef, replace bool) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+
+	if s.builtins[theme.ID] {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrBuiltinTheme, theme.ID)
+	}
+	if _, ok := s.dirThemes[theme.ID]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrDirTheme, theme.ID)
+	}
+	if _, ok := s.themes[theme.ID]; ok && !replace {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrThemeExists, theme.ID)
+	}
+	if err := s.checkTheme(theme); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	normalizeTheme(theme)
+	theme.Synthesized = nil
+
+	before := s.activeView()
+	prev, existed := s.themes[theme.ID]
 	s.themes[theme.ID] = theme
+	if err := s.saveUserThemes(); err != nil {
+		if existed {
+			s.themes[theme.ID] = prev
+		} else {
+			delete(s.themes, theme.ID)
+		}
+		s.mu.Unlock()
+		return fmt.Errorf("persist theme %s: %w", theme.ID, err)
+	}
+	after := s.activeView()
+	s.mu.Unlock()
+
 	s.logger.Info().Str("theme", theme.ID).Msg("theme registered")
+	if !reflect.DeepEqual(before, after) {
+		s.fireListeners(before, after)
+	}
+	return nil
+}
+
+// UpdateTheme replaces a user theme with a new definition. The theme ID
+// cannot be changed; an empty ID in theme is filled in from id.
+func (s *ThemesService) UpdateTheme(id string, theme *types.ThemeDef) (*types.ThemeDef, error) {
+	if theme.ID == "" {
+		theme.ID = id
+	}
+	if theme.ID != id {
+		return nil, fmt.Errorf("theme ID cannot be changed: %s -> %s", id, theme.ID)
+	}
+
+	err := s.replaceTheme(id, func(*types.ThemeDef) (*types.ThemeDef, error) {
+		return theme, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info().Str("theme", id).Msg("theme updated")
+	return theme, nil
+}
+
+// PatchTheme applies a JSON merge patch (RFC 7396) to a user theme.
+// Object members such as "colors" are merged key by key and a null
+// value removes the key; arrays such as "token_colors" are replaced.
+func (s *ThemesService) PatchTheme(id string, patch []byte) (*types.ThemeDef, error) {
+	var patchDoc any
+	if err := json.Unmarshal(patch, &patchDoc); err != nil {
+		return nil, fmt.Errorf("invalid patch JSON: %w", err)
+	}
+
+	var patched *types.ThemeDef
+	err := s.replaceTheme(id, func(current *types.ThemeDef) (*types.ThemeDef, error) {
+		var err error
+		patched, err = applyMergePatch(current, patchDoc)
+		if err != nil {
+			return nil, err
+		}
+		if patched.ID != id {
+			return nil, fmt.Errorf("theme ID cannot be changed: %s -> %s", id, patched.ID)
+		}
+		return patched, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info().Str("theme", id).Msg("theme patched")
+	return patched, nil
+}
+
+// DeleteTheme removes a user theme. Deleting the active theme switches
+// back to the configured default theme. Listeners are notified whenever
+// the active theme changes as a result, including when it inherited
+// from the deleted theme.
+func (s *ThemesService) DeleteTheme(id string) error {
+	s.mu.Lock()
+
+	if s.builtins[id] {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrBuiltinTheme, id)
+	}
+	if _, ok := s.dirThemes[id]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrDirTheme, id)
+	}
+	old, ok := s.themes[id]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrThemeNotFound, id)
+	}
+
+	before := s.activeView()
+	delete(s.themes, id)
+	if err := s.saveUserThemes(); err != nil {
+		s.themes[id] = old
+		s.mu.Unlock()
+		return fmt.Errorf("persist theme deletion %s: %w", id, err)
+	}
+
+	if s.activeID == id {
+		s.activeID = s.defaultID
+		s.savePreference()
+	}
+	after := s.activeView()
+	s.mu.Unlock()
+
+	s.logger.Info().Str("theme", id).Msg("theme deleted")
+	if !reflect.DeepEqual(before, after) {
+		s.fireListeners(before, after)
+	}
+	return nil
+}
+
+// replaceTheme swaps the user theme id for the one edit derives from it
+// and persists the store. edit runs under the write lock, so concurrent
+// edits of one theme apply in turn. If the active theme is the edited
+// theme or inherits from it, listeners are notified with the re-resolved
+// active theme.
+func (s *ThemesService) replaceTheme(id string, edit func(old *types.ThemeDef) (*types.ThemeDef, error)) error {
+	s.mu.Lock()
+
+	if s.builtins[id] {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrBuiltinTheme, id)
+	}
+	if _, ok := s.dirThemes[id]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrDirTheme, id)
+	}
+	old, ok := s.themes[id]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrThemeNotFound, id)
+	}
+	theme, err := edit(old)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	if err := s.checkTheme(theme); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	normalizeTheme(theme)
+	theme.Synthesized = nil
+
+	affectsActive := s.inheritsFrom(s.activeID, theme.ID)
+	var oldActive *types.ThemeDef
+	if affectsActive {
+		oldActive = s.view(s.themes[s.activeID])
+	}
+
+	s.themes[theme.ID] = theme
+	if err := s.saveUserThemes(); err != nil {
+		s.themes[theme.ID] = old
+		s.mu.Unlock()
+		return fmt.Errorf("persist theme %s: %w", theme.ID, err)
+	}
+
+	var newActive *types.ThemeDef
+	if affectsActive {
+		newActive = s.view(s.themes[s.activeID])
+	}
+	s.mu.Unlock()
+
+	if affectsActive {
+		s.fireListeners(oldActive, newActive)
+	}
+	return nil
+}
+
+// IsBuiltin reports whether id names a built-in theme.
+func (s *ThemesService) IsBuiltin(id string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.builtins[id]
 }
 
 // SetActiveTheme switches to a theme by ID.
@@ -52,14 +287,18 @@ func (s *ThemesService) SetActiveTheme(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	newTheme, ok := s.themes[id]
+	t, ok := s.themes[id]
 	if !ok {
-		return fmt.Errorf("theme not found: %s", id)
+		return fmt.Errorf("%w: %s", ErrThemeNotFound, id)
 	}
+	newTheme := s.view(t)
 
-	oldID := s.activeID
-	oldTheme := s.themes[oldID]
+	var oldTheme *types.ThemeDef
+	if old, ok := s.themes[s.activeID]; ok {
+		oldTheme = s.view(old)
+	}
 	s.activeID = id
+	s.pendingActive = ""
 	s.savePreference()
 
 	s.mu.Unlock()
@@ -69,35 +308,56 @@ func (s *ThemesService) SetActiveTheme(id string) error {
 	return nil
 }
 
-// GetActiveTheme returns the currently active theme.
+// GetActiveTheme returns the currently active theme, resolved against
+// any base themes it extends and completed with any missing color keys.
 func (s *ThemesService) GetActiveTheme() *types.ThemeDef {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.themes[s.activeID]
+
+	t, ok := s.themes[s.activeID]
+	if !ok {
+		return nil
+	}
+	return s.view(t)
 }
 
-// GetAvailableThemes returns all registered themes.
+// GetAvailableThemes returns all registered themes, resolved and
+// completed.
 func (s *ThemesService) GetAvailableThemes() []types.ThemeDef {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	result := make([]types.ThemeDef, 0, len(s.themes))
 	for _, t := range s.themes {
-		result = append(result, *t)
+		result = append(result, *s.view(t))
 	}
 	return result
 }
 
-// GetTheme returns a specific theme by ID.
+// GetTheme returns a specific theme by ID, resolved against any base
+// themes it extends and completed with any missing color keys.
 func (s *ThemesService) GetTheme(id string) (*types.ThemeDef, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	t, ok := s.themes[id]
 	if !ok {
-		return nil, fmt.Errorf("theme not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", ErrThemeNotFound, id)
 	}
-	return t, nil
+	return s.view(t), nil
+}
+
+// GetRawTheme returns a copy of a theme by ID exactly as it was stored,
+// without resolving its "extends" chain or filling in missing colors.
+func (s *ThemesService) GetRawTheme(id string) (*types.ThemeDef, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.themes[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrThemeNotFound, id)
+	}
+	return cloneTheme(t), nil
 }
 
 // OnDidChangeTheme registers a callback for theme changes.
@@ -107,16 +367,67 @@ func (s *ThemesService) OnDidChangeTheme(cb func(old, new *types.ThemeDef)) {
 	s.listeners = append(s.listeners, cb)
 }
 
-// ExportTheme serializes a theme to JSON bytes.
-func (s *ThemesService) ExportTheme(id string) ([]byte, error) {
+// ExportOptions controls how ExportTheme serializes a theme.
+type ExportOptions struct {
+	// Raw exports the theme as stored, keeping "extends" unresolved.
+	// By default the base chain is merged in and missing keys are
+	// completed so the output stands alone. Terminal formats ignore Raw
+	// and always export the completed terminal palette.
+	Raw bool
+	// Format is one of exporter.Formats(); empty means Orchestra JSON.
+	Format string
+	// CSS configures the css format.
+	CSS exporter.CSSOptions
+}
+
+// ExportTheme serializes a theme in opts.Format.
+func (s *ThemesService) ExportTheme(id string, opts ExportOptions) ([]byte, error) {
+	var (
+		t   *types.ThemeDef
+		err error
+	)
+	if opts.Raw && !exporter.IsTerminalFormat(opts.Format) {
+		t, err = s.GetRawTheme(id)
+	} else {
+		t, err = s.GetTheme(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if opts.Format == exporter.FormatCSS {
+		return exporter.ExportCSS(t, opts.CSS), nil
+	}
+	return exporter.Export(t, opts.Format)
+}
+
+// Stylesheet renders every theme as CSS custom properties scoped by
+// [data-theme], with prefers-color-scheme defaults: the active theme for
+// its own color scheme and the built-in theme for the other.
+func (s *ThemesService) Stylesheet(prefix string) []byte {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	t, ok := s.themes[id]
-	if !ok {
-		return nil, fmt.Errorf("theme not found: %s", id)
+	ids := make([]string, 0, len(s.themes))
+	for id := range s.themes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	themes := make([]*types.ThemeDef, len(ids))
+	for i, id := range ids {
+		themes[i] = s.view(s.themes[id])
 	}
-	return json.MarshalIndent(t, "", "  ")
+
+	light := s.view(s.themes[builtin.LightTheme().ID])
+	dark := s.view(s.themes[builtin.DarkTheme().ID])
+	if active, ok := s.themes[s.activeID]; ok {
+		switch active = s.view(active); active.Type {
+		case "light":
+			light = active
+		case "dark":
+			dark = active
+		}
+	}
+	return exporter.Stylesheet(themes, light, dark, prefix)
 }
 
 // ImportTheme deserializes a theme from JSON and registers it.
@@ -128,7 +439,10 @@ func (s *ThemesService) ImportTheme(data []byte) (*types.ThemeDef, error) {
 	if theme.ID == "" {
 		return nil, fmt.Errorf("theme ID is required")
 	}
-	s.RegisterTheme(&theme)
+	theme.Colors = colorkeys.Normalize(theme.Colors)
+	if err := s.RegisterTheme(&theme); err != nil {
+		return nil, err
+	}
 	return &theme, nil
 }
 
@@ -157,6 +471,8 @@ func (s *ThemesService) loadPreference() {
 	}
 	if _, ok := s.themes[pref.ActiveTheme]; ok {
 		s.activeID = pref.ActiveTheme
+	} else {
+		s.pendingActive = pref.ActiveTheme
 	}
 }
 
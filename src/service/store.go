@@ -0,0 +1,84 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// userThemesFile is the on-disk layout of user-themes.json.
+type userThemesFile struct {
+	Themes []*types.ThemeDef `json:"themes"`
+}
+
+func (s *ThemesService) userThemesPath() string {
+	return filepath.Join(s.storagePath, "user-themes.json")
+}
+
+// loadUserThemes registers every theme saved in user-themes.json.
+// Entries without an ID or shadowing a built-in theme are skipped.
+func (s *ThemesService) loadUserThemes() {
+	data, err := os.ReadFile(s.userThemesPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.logger.Warn().Err(err).Msg("failed to read user themes")
+		}
+		return
+	}
+
+	var file userThemesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		s.logger.Warn().Err(err).Msg("failed to parse user themes")
+		return
+	}
+
+	for _, t := range file.Themes {
+		if t == nil || t.ID == "" {
+			continue
+		}
+		if s.builtins[t.ID] {
+			s.logger.Warn().Str("theme", t.ID).Msg("ignoring stored theme that shadows a built-in")
+			continue
+		}
+		s.themes[t.ID] = t
+	}
+}
+
+// saveUserThemes writes all user themes to user-themes.json; built-in
+// and themes directory themes are not stored.
+// The file is written to a temporary path and renamed into place so a
+// crash mid-write never leaves a truncated store behind.
+func (s *ThemesService) saveUserThemes() error {
+	file := userThemesFile{Themes: make([]*types.ThemeDef, 0, len(s.themes))}
+	for id, t := range s.themes {
+		if _, ok := s.dirThemes[id]; ok || s.builtins[id] {
+			continue
+		}
+		file.Themes = append(file.Themes, t)
+	}
+	sort.Slice(file.Themes, func(i, j int) bool {
+		return file.Themes[i].ID < file.Themes[j].ID
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal user themes: %w", err)
+	}
+	if err := os.MkdirAll(s.storagePath, 0o755); err != nil {
+		return fmt.Errorf("create storage dir: %w", err)
+	}
+
+	tmp := s.userThemesPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write user themes: %w", err)
+	}
+	if err := os.Rename(tmp, s.userThemesPath()); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write user themes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"github.com/orchestra-mcp/themes/src/builtin"
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// terminalSynthesizedPrefix prefixes terminal palette fields listed in
+// ThemeDef.Synthesized, e.g. "terminal.bright_red".
+const terminalSynthesizedPrefix = "terminal."
+
+// terminalRule derives a terminal color from a completed color map.
+type terminalRule struct {
+	field  string
+	derive func(p palette) (color.Color, bool)
+}
+
+// neutral returns a gray ramp between the darker and the lighter of the
+// theme's background and foreground; t=0 is the darker one.
+func neutral(t float64) func(p palette) (color.Color, bool) {
+	return func(p palette) (color.Color, bool) {
+		bg, ok1 := p.first(colorkeys.EditorBackground, colorkeys.Background)
+		fg, ok2 := p.first(colorkeys.EditorForeground, colorkeys.Foreground)
+		if !ok1 || !ok2 {
+			return color.Color{}, false
+		}
+		dark, light := bg, fg
+		if bg.Luminance() > fg.Luminance() {
+			dark, light = fg, bg
+		}
+		return color.Mix(dark, light, t), true
+	}
+}
+
+// terminalRules fill the palette from the theme's colors: the special
+// colors from the editor, the neutrals from background and foreground,
+// and the hues from the status and accent colors. Cyan has no
+// counterpart and comes from the built-in palette.
+var terminalRules = []terminalRule{
+	{"background", copyOf(colorkeys.EditorBackground, colorkeys.Background)},
+	{"foreground", copyOf(colorkeys.EditorForeground, colorkeys.Foreground)},
+	{"cursor", copyOf(colorkeys.EditorCursor)},
+	{"selection", copyOf(colorkeys.EditorSelection)},
+	{"black", neutral(0.15)},
+	{"bright_black", neutral(0.45)},
+	{"white", neutral(0.8)},
+	{"bright_white", neutral(1)},
+	{"red", copyOf(colorkeys.Error)},
+	{"green", copyOf(colorkeys.Success)},
+	{"yellow", copyOf(colorkeys.Warning)},
+	{"blue", copyOf(colorkeys.Info)},
+	{"magenta", copyOf(colorkeys.Accent)},
+}
+
+// brightOf pairs each chromatic ANSI color with its bright variant, which
+// is derived by moving the base color away from the background.
+var brightOf = map[string]string{
+	"red":     "bright_red",
+	"green":   "bright_green",
+	"yellow":  "bright_yellow",
+	"blue":    "bright_blue",
+	"magenta": "bright_magenta",
+	"cyan":    "bright_cyan",
+}
+
+// completeTerminal returns theme's terminal palette with every field set,
+// and the fields it filled in. theme.Colors must already be complete.
+// Unset fields are taken, in order, from terminal colors the source
+// format preserved under "raw." keys, derived from the theme's colors,
+// and copied from the built-in palette of the same type.
+func completeTerminal(theme *types.ThemeDef, p palette) (*types.TerminalPalette, []string) {
+	out := &types.TerminalPalette{}
+	if theme.Terminal != nil {
+		*out = *theme.Terminal
+	}
+	if terminalComplete(out) {
+		return out, nil
+	}
+
+	var filled []string
+	set := func(field, value string) {
+		*out.Field(field) = value
+		filled = append(filled, terminalSynthesizedPrefix+field)
+	}
+
+	if raw := importer.TerminalFromRaw(theme.Source, theme.Colors); raw != nil {
+		for _, name := range types.TerminalColorNames {
+			if v := *raw.Field(name); v != "" && *out.Field(name) == "" {
+				set(name, v)
+			}
+		}
+	}
+	for _, rule := range terminalRules {
+		if *out.Field(rule.field) != "" {
+			continue
+		}
+		if c, ok := rule.derive(p); ok {
+			set(rule.field, c.Hex())
+		}
+	}
+
+	fallback := builtin.DarkTheme().Terminal
+	if p.light {
+		fallback = builtin.LightTheme().Terminal
+	}
+	if out.Cyan == "" {
+		set("cyan", fallback.Cyan)
+	}
+	for base, bright := range brightOf {
+		if *out.Field(bright) != "" {
+			continue
+		}
+		if c, err := color.Parse(*out.Field(base)); err == nil {
+			set(bright, p.shift(c, 0.08).Hex())
+		}
+	}
+	for _, name := range types.TerminalColorNames {
+		if *out.Field(name) == "" {
+			set(name, *fallback.Field(name))
+		}
+	}
+	return out, filled
+}
+
+// terminalComplete reports whether every field of t is set.
+func terminalComplete(t *types.TerminalPalette) bool {
+	if t == nil {
+		return false
+	}
+	for _, name := range types.TerminalColorNames {
+		if *t.Field(name) == "" {
+			return false
+		}
+	}
+	return true
+}
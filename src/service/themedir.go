@@ -0,0 +1,292 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// ErrDirTheme is returned when a write targets a theme loaded from the
+// themes directory; such themes change only when their file does.
+var ErrDirTheme = errors.New("theme is managed by the themes directory")
+
+// DefaultPollInterval is how often a ThemeDirWatcher rescans by default.
+const DefaultPollInterval = 2 * time.Second
+
+// fileStamp identifies one version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func (a fileStamp) equal(b fileStamp) bool {
+	return a.size == b.size && a.modTime.Equal(b.modTime)
+}
+
+// dirFile is a file seen in the themes directory, the ID of the theme
+// last loaded from it, if any, and why its current version failed to
+// load, if it did.
+type dirFile struct {
+	stamp fileStamp
+	id    string
+	err   string
+}
+
+// ThemeDirWatcher keeps the themes in a directory registered, polling it
+// for added, changed and removed files. Polling works on every platform
+// and file system, including network mounts.
+type ThemeDirWatcher struct {
+	svc      *ThemesService
+	dir      string
+	interval time.Duration
+
+	mu    sync.Mutex // serializes Sync
+	files map[string]dirFile
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// WatchThemeDir loads every theme file in dir, in any format
+// importer.Import understands (themes of formats without a name are
+// named after their file), then rescans dir every interval until the
+// returned watcher is stopped. Directory themes are not written to the
+// user theme store and cannot be updated or deleted through the service.
+// A missing directory is treated as empty, so it may be created later.
+func (s *ThemesService) WatchThemeDir(dir string, interval time.Duration) *ThemeDirWatcher {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	w := &ThemeDirWatcher{
+		svc:      s,
+		dir:      dir,
+		interval: interval,
+		files:    make(map[string]dirFile),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	w.Sync()
+	go w.run()
+	return w
+}
+
+func (w *ThemeDirWatcher) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.Sync()
+		}
+	}
+}
+
+// Stop ends polling and waits for a scan in progress to finish. Loaded
+// themes stay registered. Stop may be called more than once.
+func (w *ThemeDirWatcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+// Sync rescans the directory immediately, loading new and changed files
+// and unregistering the themes of removed ones. A file that fails to
+// import leaves the theme it last defined in place and is retried on
+// every scan, since it may depend on a theme that appears later.
+func (w *ThemeDirWatcher) Sync() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entries, err := os.ReadDir(w.dir)
+	if err != nil && !os.IsNotExist(err) {
+		w.svc.logger.Warn().Err(err).Str("dir", w.dir).Msg("failed to scan themes directory")
+		return
+	}
+
+	seen := make(map[string]bool, len(entries))
+	var changed []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		seen[name] = true
+		stamp := fileStamp{modTime: info.ModTime(), size: info.Size()}
+		f, known := w.files[name]
+		if known && f.stamp.equal(stamp) && f.err == "" {
+			continue
+		}
+		if !f.stamp.equal(stamp) {
+			f.err = ""
+		}
+		f.stamp = stamp
+		w.files[name] = f
+		changed = append(changed, name)
+	}
+
+	for name, f := range w.files {
+		if seen[name] {
+			continue
+		}
+		delete(w.files, name)
+		if f.id != "" {
+			w.svc.unloadDirTheme(f.id)
+		}
+	}
+
+	// A theme may extend one defined by a file loaded later in the same
+	// scan, so failed files are retried while others keep succeeding.
+	failed := make(map[string]error)
+	for pending := changed; len(pending) > 0; {
+		var retry []string
+		for _, name := range pending {
+			if err := w.load(name); err != nil {
+				failed[name] = err
+				retry = append(retry, name)
+			} else {
+				delete(failed, name)
+			}
+		}
+		if len(retry) == len(pending) {
+			break
+		}
+		pending = retry
+	}
+	// Retried files are only logged again when the reason changes.
+	for name, err := range failed {
+		f := w.files[name]
+		if f.err == err.Error() {
+			continue
+		}
+		f.err = err.Error()
+		w.files[name] = f
+		w.svc.logger.Warn().Err(err).Str("file", filepath.Join(w.dir, name)).Msg("failed to load theme file")
+	}
+}
+
+// load imports the file name and registers its theme.
+func (w *ThemeDirWatcher) load(name string) error {
+	path := filepath.Join(w.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	theme, err := importer.ImportFile(name, data)
+	if err != nil {
+		return err
+	}
+	if theme.ID == "" {
+		base := strings.TrimSuffix(name, filepath.Ext(name))
+		theme.ID = strings.ReplaceAll(strings.ToLower(base), " ", "-")
+	}
+
+	f := w.files[name]
+	if err := w.svc.loadDirTheme(path, f.id, theme); err != nil {
+		return err
+	}
+	f.id = theme.ID
+	f.err = ""
+	w.files[name] = f
+	return nil
+}
+
+// loadDirTheme registers theme as loaded from the file at path. prevID is
+// the theme the file defined before, which is replaced. Listeners are
+// notified if the active theme changes as a result, which also happens
+// when the stored preference names this theme.
+func (s *ThemesService) loadDirTheme(path, prevID string, theme *types.ThemeDef) error {
+	s.mu.Lock()
+
+	if owner, ok := s.dirThemes[theme.ID]; ok && owner != path {
+		s.mu.Unlock()
+		return fmt.Errorf("theme %s is already loaded from %s", theme.ID, owner)
+	}
+	if _, ok := s.dirThemes[theme.ID]; !ok {
+		if s.builtins[theme.ID] {
+			s.mu.Unlock()
+			return fmt.Errorf("%w: %s", ErrBuiltinTheme, theme.ID)
+		}
+		if _, exists := s.themes[theme.ID]; exists {
+			s.mu.Unlock()
+			return fmt.Errorf("theme %s is already registered", theme.ID)
+		}
+	}
+	if err := s.checkTheme(theme); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	normalizeTheme(theme)
+	theme.Synthesized = nil
+
+	before := s.activeView()
+	if prevID != "" && prevID != theme.ID {
+		s.removeDirTheme(prevID)
+	}
+	s.themes[theme.ID] = theme
+	s.dirThemes[theme.ID] = path
+	if s.pendingActive == theme.ID {
+		s.activeID = theme.ID
+		s.pendingActive = ""
+	}
+	after := s.activeView()
+	s.mu.Unlock()
+
+	s.logger.Info().Str("theme", theme.ID).Str("file", path).Msg("theme file loaded")
+	if !reflect.DeepEqual(before, after) {
+		s.fireListeners(before, after)
+	}
+	return nil
+}
+
+// unloadDirTheme unregisters a theme whose file was removed.
+func (s *ThemesService) unloadDirTheme(id string) {
+	s.mu.Lock()
+	before := s.activeView()
+	s.removeDirTheme(id)
+	after := s.activeView()
+	s.mu.Unlock()
+
+	s.logger.Info().Str("theme", id).Msg("theme file removed")
+	if !reflect.DeepEqual(before, after) {
+		s.fireListeners(before, after)
+	}
+}
+
+// removeDirTheme drops a directory theme, falling back to the default
+// theme if it was active. Callers must hold s.mu.
+func (s *ThemesService) removeDirTheme(id string) {
+	if _, ok := s.dirThemes[id]; !ok {
+		return
+	}
+	delete(s.themes, id)
+	delete(s.dirThemes, id)
+	if s.activeID == id {
+		s.activeID = s.defaultID
+		s.savePreference()
+	}
+}
+
+// activeView returns the resolved active theme, or nil. Callers must
+// hold s.mu.
+func (s *ThemesService) activeView() *types.ThemeDef {
+	t, ok := s.themes[s.activeID]
+	if !ok {
+		return nil
+	}
+	return s.view(t)
+}
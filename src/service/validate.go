@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/orchestra-mcp/themes/src/types"
+	"github.com/orchestra-mcp/themes/src/validator"
+)
+
+// ValidateTheme checks theme against the schema and against the
+// registered themes (its "extends" chain must resolve) without storing
+// it.
+func (s *ThemesService) ValidateTheme(theme *types.ThemeDef) validator.Diagnostics {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	diags := validator.Validate(theme)
+	if err := s.checkExtends(theme); err != nil && !hasCode(diags, validator.CodeInvalidExtends) {
+		diags = append(diags, validator.Diagnostic{
+			Severity: validator.SeverityError,
+			Code:     validator.CodeInvalidExtends,
+			Path:     "$.extends",
+			Message:  err.Error(),
+		})
+	}
+	return diags
+}
+
+// checkTheme validates theme before it is stored. Schema errors wrap
+// ErrInvalidTheme; broken inheritance wraps ErrInvalidExtends. Callers
+// must hold s.mu.
+func (s *ThemesService) checkTheme(theme *types.ThemeDef) error {
+	if err := s.checkExtends(theme); err != nil {
+		return err
+	}
+	if err := validator.Validate(theme).Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTheme, err)
+	}
+	return nil
+}
+
+func hasCode(diags validator.Diagnostics, code string) bool {
+	for _, d := range diags {
+		if d.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -8,8 +8,112 @@ type ThemeDef struct {
 	Author      string            `json:"author"`
 	Type        string            `json:"type"`
 	Source      string            `json:"source,omitempty"`
+	Extends     string            `json:"extends,omitempty"`
 	Colors      map[string]string `json:"colors"`
 	TokenColors []TokenColor      `json:"token_colors,omitempty"`
+	// SemanticHighlighting asks editors that support semantic tokens to
+	// apply SemanticTokenColors. Nil inherits the base theme's setting,
+	// so a derived theme can also switch it off.
+	SemanticHighlighting *bool                `json:"semantic_highlighting,omitempty"`
+	SemanticTokenColors  []SemanticTokenColor `json:"semantic_token_colors,omitempty"`
+	// Terminal is the palette of the integrated terminal. Themes that do
+	// not define one get a derived palette on read.
+	Terminal *TerminalPalette `json:"terminal,omitempty"`
+	// Synthesized lists color keys the service filled in because the
+	// theme did not define them. It is computed on read, never stored.
+	Synthesized []string `json:"synthesized,omitempty"`
+}
+
+// TerminalPalette holds the colors of a terminal emulator: the eight ANSI
+// colors and their bright variants, followed by the default background,
+// foreground, cursor and selection. Empty fields are unset.
+type TerminalPalette struct {
+	Black         string `json:"black,omitempty"`
+	Red           string `json:"red,omitempty"`
+	Green         string `json:"green,omitempty"`
+	Yellow        string `json:"yellow,omitempty"`
+	Blue          string `json:"blue,omitempty"`
+	Magenta       string `json:"magenta,omitempty"`
+	Cyan          string `json:"cyan,omitempty"`
+	White         string `json:"white,omitempty"`
+	BrightBlack   string `json:"bright_black,omitempty"`
+	BrightRed     string `json:"bright_red,omitempty"`
+	BrightGreen   string `json:"bright_green,omitempty"`
+	BrightYellow  string `json:"bright_yellow,omitempty"`
+	BrightBlue    string `json:"bright_blue,omitempty"`
+	BrightMagenta string `json:"bright_magenta,omitempty"`
+	BrightCyan    string `json:"bright_cyan,omitempty"`
+	BrightWhite   string `json:"bright_white,omitempty"`
+	Background    string `json:"background,omitempty"`
+	Foreground    string `json:"foreground,omitempty"`
+	Cursor        string `json:"cursor,omitempty"`
+	Selection     string `json:"selection,omitempty"`
+}
+
+// TerminalColorNames lists the TerminalPalette fields by their JSON
+// names, the 16 ANSI colors first in index order.
+var TerminalColorNames = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright_black", "bright_red", "bright_green", "bright_yellow",
+	"bright_blue", "bright_magenta", "bright_cyan", "bright_white",
+	"background", "foreground", "cursor", "selection",
+}
+
+// Field returns a pointer to the field with the given JSON name, or nil
+// if there is no such field.
+func (p *TerminalPalette) Field(name string) *string {
+	switch name {
+	case "black":
+		return &p.Black
+	case "red":
+		return &p.Red
+	case "green":
+		return &p.Green
+	case "yellow":
+		return &p.Yellow
+	case "blue":
+		return &p.Blue
+	case "magenta":
+		return &p.Magenta
+	case "cyan":
+		return &p.Cyan
+	case "white":
+		return &p.White
+	case "bright_black":
+		return &p.BrightBlack
+	case "bright_red":
+		return &p.BrightRed
+	case "bright_green":
+		return &p.BrightGreen
+	case "bright_yellow":
+		return &p.BrightYellow
+	case "bright_blue":
+		return &p.BrightBlue
+	case "bright_magenta":
+		return &p.BrightMagenta
+	case "bright_cyan":
+		return &p.BrightCyan
+	case "bright_white":
+		return &p.BrightWhite
+	case "background":
+		return &p.Background
+	case "foreground":
+		return &p.Foreground
+	case "cursor":
+		return &p.Cursor
+	case "selection":
+		return &p.Selection
+	}
+	return nil
+}
+
+// ANSI returns the 16 ANSI colors in index order.
+func (p *TerminalPalette) ANSI() [16]string {
+	var out [16]string
+	for i := range out {
+		out[i] = *p.Field(TerminalColorNames[i])
+	}
+	return out
 }
 
 // TokenColor defines syntax highlighting colors.
@@ -19,6 +123,29 @@ type TokenColor struct {
 	Settings map[string]string `json:"settings"`
 }
 
+// SemanticTokenColor styles the semantic tokens matched by a selector of
+// the form "type.modifier1.modifier2:language", where type may be "*"
+// and modifiers and language are optional.
+type SemanticTokenColor struct {
+	Selector  string             `json:"selector"`
+	TokenType string             `json:"token_type"`
+	Modifiers []string           `json:"modifiers,omitempty"`
+	Language  string             `json:"language,omitempty"`
+	Style     SemanticTokenStyle `json:"style"`
+}
+
+// SemanticTokenStyle is the style applied by a SemanticTokenColor. The
+// font flags are pointers so that an explicit false, which turns a style
+// off, is distinguishable from an unset flag.
+type SemanticTokenStyle struct {
+	Foreground    string `json:"foreground,omitempty"`
+	FontStyle     string `json:"font_style,omitempty"`
+	Bold          *bool  `json:"bold,omitempty"`
+	Italic        *bool  `json:"italic,omitempty"`
+	Underline     *bool  `json:"underline,omitempty"`
+	Strikethrough *bool  `json:"strikethrough,omitempty"`
+}
+
 // ThemeChangeEvent is emitted when the active theme changes.
 type ThemeChangeEvent struct {
 	OldThemeID string `json:"old_theme_id"`
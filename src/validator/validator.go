@@ -0,0 +1,258 @@
+// Package validator checks theme definitions against the theme schema
+// and reports problems as diagnostics with a severity and a JSON path.
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/types"
+)
+
+// Diagnostic severities.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Diagnostic codes.
+const (
+	CodeMissingID        = "missing_id"
+	CodeInvalidID        = "invalid_id"
+	CodeMissingName      = "missing_name"
+	CodeMissingType      = "missing_type"
+	CodeInvalidType      = "invalid_type"
+	CodeInvalidColor     = "invalid_color"
+	CodeUnknownKey       = "unknown_key"
+	CodeAliasKey         = "alias_key"
+	CodeEmptyScope       = "empty_scope"
+	CodeUnknownSetting   = "unknown_setting"
+	CodeInvalidFontStyle = "invalid_font_style"
+	CodeInvalidExtends   = "invalid_extends"
+	CodeInvalidSelector  = "invalid_selector"
+)
+
+// Diagnostic is a single validation finding.
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+// Diagnostics is a list of findings for one theme.
+type Diagnostics []Diagnostic
+
+// HasErrors reports whether any diagnostic has error severity.
+func (d Diagnostics) HasErrors() bool {
+	for _, diag := range d {
+		if diag.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns only the error-severity diagnostics.
+func (d Diagnostics) Errors() Diagnostics {
+	var out Diagnostics
+	for _, diag := range d {
+		if diag.Severity == SeverityError {
+			out = append(out, diag)
+		}
+	}
+	return out
+}
+
+// Err returns an *Error when d contains errors, and nil otherwise.
+func (d Diagnostics) Err() error {
+	if !d.HasErrors() {
+		return nil
+	}
+	return &Error{Diagnostics: d}
+}
+
+// Error is returned when a theme fails validation.
+type Error struct {
+	Diagnostics Diagnostics
+}
+
+func (e *Error) Error() string {
+	errs := e.Diagnostics.Errors()
+	if len(errs) == 0 {
+		return "theme validation failed"
+	}
+	msg := fmt.Sprintf("%s: %s", errs[0].Path, errs[0].Message)
+	if len(errs) > 1 {
+		msg += fmt.Sprintf(" (and %d more errors)", len(errs)-1)
+	}
+	return msg
+}
+
+// Theme types accepted in ThemeDef.Type.
+var validTypes = map[string]bool{
+	"light":         true,
+	"dark":          true,
+	"high-contrast": true,
+}
+
+var validFontStyles = map[string]bool{
+	"italic":        true,
+	"bold":          true,
+	"underline":     true,
+	"strikethrough": true,
+}
+
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Validate checks theme and returns its diagnostics in a stable order:
+// top-level fields first, then colors by key, then the terminal palette,
+// then token colors, then semantic token colors.
+func Validate(theme *types.ThemeDef) Diagnostics {
+	var d Diagnostics
+	add := func(severity, code, path, format string, args ...any) {
+		d = append(d, Diagnostic{
+			Severity: severity,
+			Code:     code,
+			Path:     path,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	switch {
+	case theme.ID == "":
+		add(SeverityError, CodeMissingID, "$.id", "theme ID is required")
+	case !idPattern.MatchString(theme.ID):
+		add(SeverityError, CodeInvalidID, "$.id",
+			"theme ID %q must be lowercase letters, digits, '.', '_' or '-'", theme.ID)
+	}
+
+	if theme.Name == "" {
+		add(SeverityWarning, CodeMissingName, "$.name", "theme has no display name")
+	}
+
+	switch {
+	case theme.Type == "" && theme.Extends == "":
+		add(SeverityWarning, CodeMissingType, "$.type", "theme type is not set; it will be treated as dark")
+	case theme.Type != "" && !validTypes[theme.Type]:
+		add(SeverityError, CodeInvalidType, "$.type",
+			"theme type %q must be one of light, dark, high-contrast", theme.Type)
+	}
+
+	if theme.Extends != "" && theme.Extends == theme.ID {
+		add(SeverityError, CodeInvalidExtends, "$.extends", "theme cannot extend itself")
+	}
+
+	keys := make([]string, 0, len(theme.Colors))
+	for key := range theme.Colors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		path := colorPath(key)
+		value := theme.Colors[key]
+		isRaw := strings.HasPrefix(key, colorkeys.RawPrefix)
+
+		if _, err := color.Parse(value); err != nil && !isRaw {
+			add(SeverityError, CodeInvalidColor, path, "%v", err)
+		}
+		switch canonical := colorkeys.Canonical(key); {
+		case isRaw:
+		case canonical == "":
+			add(SeverityWarning, CodeUnknownKey, path, "%q is not a known color key", key)
+		case canonical != key:
+			add(SeverityInfo, CodeAliasKey, path, "%q is an alias of %q", key, canonical)
+		}
+	}
+
+	if theme.Terminal != nil {
+		for _, name := range types.TerminalColorNames {
+			value := *theme.Terminal.Field(name)
+			if value == "" {
+				continue
+			}
+			if _, err := color.Parse(value); err != nil {
+				add(SeverityError, CodeInvalidColor, "$.terminal."+name, "%v", err)
+			}
+		}
+	}
+
+	for i, tc := range theme.TokenColors {
+		validateTokenColor(i, tc, add)
+	}
+	for i, sc := range theme.SemanticTokenColors {
+		validateSemanticTokenColor(i, sc, add)
+	}
+	return d
+}
+
+func validateTokenColor(i int, tc types.TokenColor, add func(severity, code, path, format string, args ...any)) {
+	base := fmt.Sprintf("$.token_colors[%d]", i)
+
+	if len(tc.Scope) == 0 {
+		add(SeverityWarning, CodeEmptyScope, base+".scope",
+			"token rule has no scope and applies to all tokens")
+	}
+	for j, scope := range tc.Scope {
+		if strings.TrimSpace(scope) == "" {
+			add(SeverityError, CodeEmptyScope, fmt.Sprintf("%s.scope[%d]", base, j), "scope selector is empty")
+		}
+	}
+
+	keys := make([]string, 0, len(tc.Settings))
+	for key := range tc.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		path := base + ".settings." + key
+		value := tc.Settings[key]
+		switch key {
+		case "foreground", "background":
+			if _, err := color.Parse(value); err != nil {
+				add(SeverityError, CodeInvalidColor, path, "%v", err)
+			}
+		case "fontStyle":
+			for _, style := range strings.Fields(value) {
+				if !validFontStyles[style] && style != "normal" {
+					add(SeverityError, CodeInvalidFontStyle, path,
+						"font style %q must be italic, bold, underline or strikethrough", style)
+				}
+			}
+		default:
+			add(SeverityWarning, CodeUnknownSetting, path, "unknown token setting %q", key)
+		}
+	}
+}
+
+func validateSemanticTokenColor(i int, sc types.SemanticTokenColor, add func(severity, code, path, format string, args ...any)) {
+	base := fmt.Sprintf("$.semantic_token_colors[%d]", i)
+
+	if _, _, _, ok := importer.ParseSemanticSelector(sc.Selector); !ok {
+		add(SeverityError, CodeInvalidSelector, base+".selector",
+			"semantic token selector %q must look like type.modifier:language", sc.Selector)
+	}
+	if sc.Style.Foreground != "" {
+		if _, err := color.Parse(sc.Style.Foreground); err != nil {
+			add(SeverityError, CodeInvalidColor, base+".style.foreground", "%v", err)
+		}
+	}
+	for _, style := range strings.Fields(sc.Style.FontStyle) {
+		if !validFontStyles[style] {
+			add(SeverityError, CodeInvalidFontStyle, base+".style.font_style",
+				"font style %q must be italic, bold, underline or strikethrough", style)
+		}
+	}
+}
+
+// colorPath returns the JSON path of a color key. Keys contain dots, so
+// bracket notation is used.
+func colorPath(key string) string {
+	return fmt.Sprintf("$.colors['%s']", key)
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/service"
+	"github.com/orchestra-mcp/themes/src/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorNormalize(t *testing.T) {
+	cases := map[string]string{
+		"#fff":                      "#FFFFFF",
+		"#FFF8":                     "#FFFFFF88",
+		"#1e1e2e":                   "#1E1E2E",
+		"#1e1e2e80":                 "#1E1E2E80",
+		"#1e1e2eff":                 "#1E1E2E",
+		"  #ABCDEF  ":               "#ABCDEF",
+		"rgb(255, 0, 0)":            "#FF0000",
+		"rgba(0, 0, 255, 0.5)":      "#0000FF80",
+		"rgb(0 128 0 / 50%)":        "#00800080",
+		"rgb(100%, 50%, 0%)":        "#FF8000",
+		"hsl(120, 100%, 50%)":       "#00FF00",
+		"hsla(240deg 100% 50% / 1)": "#0000FF",
+		"hsl(0.5turn 100% 25%)":     "#008080",
+		"oklch(62.8% 0.2577 29.23)": "#FF0000",
+		"oklch(1 0 0)":              "#FFFFFF",
+		"oklch(0% 0 0)":             "#000000",
+		"RebeccaPurple":             "#663399",
+		"white":                     "#FFFFFF",
+		"transparent":               "#00000000",
+	}
+	for in, want := range cases {
+		got, err := color.Normalize(in)
+		if assert.NoError(t, err, in) {
+			assert.Equal(t, want, got, in)
+		}
+	}
+}
+
+func TestColorParseInvalid(t *testing.T) {
+	for _, in := range []string{
+		"", "#", "#12", "#12345", "#1234567", "#GGGGGG", "123456",
+		"rgb(1, 2)", "rgb(a, b, c)", "hsl(1, 2, 3, 4, 5)", "cmyk(0, 0, 0, 0)",
+		"notacolor", "rgb(0 0 0 /)",
+		"rgb(nan, 0, 0)", "rgb(0 0 0 / NaN)", "rgb(inf%, 0%, 0%)",
+		"hsl(inf 50% 50%)", "hsl(-infinity, 50%, 50%)", "oklch(0.5 0.1 nan)",
+	} {
+		assert.False(t, color.Valid(in), "%q should be invalid", in)
+	}
+}
+
+func TestColorOKLCHRoundTrip(t *testing.T) {
+	for _, hex := range []string{"#1E1E2E", "#89B4FA", "#F38BA8", "#FFFFFF", "#000000"} {
+		c := color.MustParse(hex)
+		l, ch, h := c.OKLCH()
+		assert.Equal(t, hex, color.FromOKLCH(l, ch, h, 1).Hex())
+	}
+}
+
+func TestColorOKLCHGamutMapping(t *testing.T) {
+	// Far outside sRGB; chroma is reduced rather than channels clipped.
+	c := color.FromOKLCH(0.7, 0.5, 150, 1)
+	l, _, h := c.OKLCH()
+	assert.InDelta(t, 0.7, l, 0.01)
+	assert.InDelta(t, 150, h, 2)
+}
+
+func TestColorIsLight(t *testing.T) {
+	assert.True(t, color.MustParse("#FFF").IsLight())
+	assert.True(t, color.MustParse("hsl(60, 100%, 80%)").IsLight())
+	assert.False(t, color.MustParse("#1E1E2E").IsLight())
+	assert.False(t, color.MustParse("navy").IsLight())
+}
+
+func TestTmThemeShortHexDetectedAsLight(t *testing.T) {
+	data := []byte(`<plist version="1.0"><dict>
+	<key>name</key><string>Short</string>
+	<key>settings</key><array><dict><key>settings</key><dict>
+		<key>background</key><string>#FFF</string>
+		<key>foreground</key><string>#333</string>
+	</dict></dict></array>
+	</dict></plist>`)
+	theme, err := importer.ImportTmTheme(data)
+	require.NoError(t, err)
+	assert.Equal(t, "light", theme.Type)
+	assert.Equal(t, "#FFFFFF", theme.Colors["background"])
+	assert.Equal(t, "#FFF", theme.Colors["raw.background"], "raw values are preserved")
+}
+
+func TestImporterDropsInvalidMappedColors(t *testing.T) {
+	theme, err := importer.ImportVSCode([]byte(`{
+		"name": "Broken",
+		"colors": {"editor.background": "not-a-color", "editor.foreground": "#ABCDEF80"},
+		"tokenColors": [{"scope": "comment", "settings": {"foreground": "bogus", "fontStyle": "italic"}}]
+	}`))
+	require.NoError(t, err)
+	assert.NotContains(t, theme.Colors, "editor.background")
+	assert.Equal(t, "not-a-color", theme.Colors["raw.editor.background"])
+	assert.Equal(t, "#ABCDEF80", theme.Colors["editor.foreground"])
+	assert.NotContains(t, theme.TokenColors[0].Settings, "foreground")
+	assert.Equal(t, "italic", theme.TokenColors[0].Settings["fontStyle"])
+}
+
+func TestServiceNormalizesColors(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:     "css",
+		Colors: map[string]string{
+			"background": "rgb(0, 0, 0)",
+			"raw.weird":  "underline",
+			"raw.hex":    "#abcdef",
+			"raw.css":    "rgb(1, 2, 3)",
+		},
+		TokenColors: []types.TokenColor{
+			{Scope: []string{"comment"}, Settings: map[string]string{"foreground": "gray"}},
+		},
+	}))
+
+	got, err := svc.GetRawTheme("css")
+	require.NoError(t, err)
+	assert.Equal(t, "#000000", got.Colors["background"])
+	assert.Equal(t, "underline", got.Colors["raw.weird"])
+	assert.Equal(t, "#abcdef", got.Colors["raw.hex"], "raw keys are kept verbatim")
+	assert.Equal(t, "rgb(1, 2, 3)", got.Colors["raw.css"])
+	assert.Equal(t, "#808080", got.TokenColors[0].Settings["foreground"])
+}
+
+func TestServiceRejectsInvalidColors(t *testing.T) {
+	svc := newTestService(t)
+	err := svc.RegisterTheme(&types.ThemeDef{ID: "bad", Colors: map[string]string{"background": "nope"}})
+	assert.ErrorIs(t, err, service.ErrInvalidTheme)
+
+	_, err = svc.ImportTheme([]byte(`{"id":"bad2","token_colors":[{"scope":["x"],"settings":{"foreground":"#12"}}]}`))
+	assert.ErrorIs(t, err, service.ErrInvalidTheme)
+
+	registerCustom(t, svc, "good")
+	_, err = svc.PatchTheme("good", []byte(`{"colors":{"accent":"rgb(1,2)"}}`))
+	assert.ErrorIs(t, err, service.ErrInvalidTheme)
+}
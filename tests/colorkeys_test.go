@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/orchestra-mcp/themes/src/builtin"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorKeysRegistryComplete(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, k := range colorkeys.All() {
+		assert.False(t, seen[k.Name], "duplicate key %q", k.Name)
+		seen[k.Name] = true
+		assert.NotEmpty(t, k.Description, "key %q has no description", k.Name)
+		assert.NotEmpty(t, k.Group, "key %q has no group", k.Name)
+		assert.NotEmpty(t, k.Light, "key %q has no light default", k.Name)
+		assert.NotEmpty(t, k.Dark, "key %q has no dark default", k.Name)
+	}
+}
+
+func TestColorKeysLookupAlias(t *testing.T) {
+	k, ok := colorkeys.Lookup("bg-primary")
+	require.True(t, ok)
+	assert.Equal(t, colorkeys.Background, k.Name)
+
+	assert.Equal(t, colorkeys.EditorCursor, colorkeys.Canonical("caret"))
+	assert.Equal(t, colorkeys.EditorCursor, colorkeys.Canonical("editor.cursor"))
+	assert.Equal(t, "", colorkeys.Canonical("tab.activeBackground"))
+
+	assert.True(t, colorkeys.IsKnown("raw.tab.activeBackground"))
+	assert.False(t, colorkeys.IsKnown("made.up"))
+}
+
+func TestColorKeysNormalize(t *testing.T) {
+	got := colorkeys.Normalize(map[string]string{
+		"bg-primary":   "#111111",
+		"text-primary": "#EEEEEE",
+		"foreground":   "#FFFFFF",
+		"custom.key":   "#123456",
+	})
+	assert.Equal(t, map[string]string{
+		"background": "#111111",
+		"foreground": "#FFFFFF",
+		"custom.key": "#123456",
+	}, got)
+}
+
+func TestBuiltinThemesUseRegistryKeys(t *testing.T) {
+	for _, theme := range builtin.BuiltinThemes() {
+		assert.Len(t, theme.Colors, len(colorkeys.Names()), theme.ID)
+		for _, name := range colorkeys.Names() {
+			assert.Contains(t, theme.Colors, name, theme.ID)
+		}
+	}
+}
+
+func TestImportersProduceCanonicalKeys(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"vscode":  sampleVSCodeTheme,
+		"tmtheme": sampleTmTheme,
+	} {
+		theme, err := importer.Import(data)
+		require.NoError(t, err, name)
+		for key := range theme.Colors {
+			if _, ok := colorkeys.Lookup(key); ok {
+				assert.Equal(t, key, colorkeys.Canonical(key), "%s produced alias %q", name, key)
+				continue
+			}
+			assert.True(t, colorkeys.IsKnown(key), "%s produced unknown key %q", name, key)
+		}
+	}
+}
+
+func TestImportOrchestraNormalizesAliases(t *testing.T) {
+	theme, err := importer.Import([]byte(`{"id":"legacy","colors":{"bg-primary":"#000000","caret":"#FFFFFF"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "#000000", theme.Colors["background"])
+	assert.Equal(t, "#FFFFFF", theme.Colors["editor.cursor"])
+	assert.NotContains(t, theme.Colors, "bg-primary")
+}
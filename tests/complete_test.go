@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/orchestra-mcp/themes/src/builtin"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteFillsAllKeys(t *testing.T) {
+	svc := newTestService(t)
+	imported, err := importer.ImportTmTheme(sampleTmTheme)
+	require.NoError(t, err)
+	require.NoError(t, svc.RegisterTheme(imported))
+
+	got, err := svc.GetTheme(imported.ID)
+	require.NoError(t, err)
+	for _, key := range colorkeys.Names() {
+		assert.Contains(t, got.Colors, key)
+	}
+	assert.Equal(t, "#272822", got.Colors["background"], "own colors are kept")
+	assert.NotContains(t, got.Synthesized, "background")
+	assert.Contains(t, got.Synthesized, "border")
+	assert.Contains(t, got.Synthesized, "error")
+
+	raw, err := svc.GetRawTheme(imported.ID)
+	require.NoError(t, err)
+	assert.Empty(t, raw.Synthesized)
+	assert.NotContains(t, raw.Colors, "border", "stored theme is not modified")
+}
+
+func TestCompleteDerivesFromOwnColors(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:   "sparse",
+		Type: "dark",
+		Colors: map[string]string{
+			"background": "#202020",
+			"foreground": "#E0E0E0",
+			"primary":    "#FFCC00",
+		},
+	}))
+
+	got, err := svc.GetTheme("sparse")
+	require.NoError(t, err)
+	assert.Equal(t, "#202020", got.Colors["editor.background"])
+	assert.Equal(t, "#FFCC00", got.Colors["button.background"])
+	assert.Equal(t, "#202020", got.Colors["button.foreground"], "dark text on a light button")
+	assert.Equal(t, "#3A3A3A", got.Colors["border"], "border is background shifted lighter")
+	assert.Equal(t, builtin.DarkTheme().Colors["error"], got.Colors["error"])
+}
+
+func TestCompleteFallsBackToSameTypeBuiltin(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{ID: "empty-light", Type: "light"}))
+
+	got, err := svc.GetTheme("empty-light")
+	require.NoError(t, err)
+	assert.Equal(t, builtin.LightTheme().Colors, got.Colors)
+	assert.Len(t, got.Synthesized, len(colorkeys.Names())+len(types.TerminalColorNames))
+}
+
+func TestCompleteLeavesBuiltinsUntouched(t *testing.T) {
+	svc := newTestService(t)
+	got, err := svc.GetTheme("orchestra-dark")
+	require.NoError(t, err)
+	assert.Empty(t, got.Synthesized)
+}
@@ -0,0 +1,274 @@
+package tests
+
+import (
+	"encoding/json"
+
+	"testing"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/contrast"
+	"github.com/orchestra-mcp/themes/src/service"
+	"github.com/orchestra-mcp/themes/src/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findCheck(r *contrast.Report, fg, bg string) *contrast.Check {
+	for i := range r.Checks {
+		if r.Checks[i].Foreground == fg && r.Checks[i].Background == bg {
+			return &r.Checks[i]
+		}
+	}
+	return nil
+}
+
+func TestContrastRatio(t *testing.T) {
+	black, white := color.MustParse("#000"), color.MustParse("#FFF")
+	assert.InDelta(t, 21, contrast.Ratio(black, white), 0.001)
+	assert.InDelta(t, 21, contrast.Ratio(white, black), 0.001)
+	assert.InDelta(t, 1, contrast.Ratio(white, white), 0.001)
+	assert.InDelta(t, 4.48, contrast.Ratio(color.MustParse("#777"), white), 0.01)
+}
+
+func TestContrastAPCA(t *testing.T) {
+	black, white := color.MustParse("#000"), color.MustParse("#FFF")
+	assert.InDelta(t, 106.04, contrast.APCA(black, white), 0.1)
+	assert.InDelta(t, -107.88, contrast.APCA(white, black), 0.1)
+	assert.InDelta(t, 63.06, contrast.APCA(color.MustParse("#888"), white), 0.1)
+	assert.Equal(t, 0.0, contrast.APCA(white, white))
+}
+
+func TestContrastAuditFlagsFailures(t *testing.T) {
+	theme := &types.ThemeDef{
+		ID: "low",
+		Colors: map[string]string{
+			"statusbar.background": "#2563EB",
+			"statusbar.foreground": "#3B82F6",
+			"editor.background":    "#FFFFFF",
+			"editor.foreground":    "#000000",
+			"editor.cursor":        "#00000080",
+		},
+		TokenColors: []types.TokenColor{
+			{Scope: []string{"comment"}, Settings: map[string]string{"foreground": "#CCCCCC"}},
+			{Scope: []string{"keyword"}, Settings: map[string]string{"fontStyle": "bold"}},
+		},
+	}
+	report := contrast.Audit(theme, contrast.Options{APCA: true})
+
+	status := findCheck(&report, "statusbar.foreground", "statusbar.background")
+	require.NotNil(t, status)
+	assert.False(t, status.AA)
+	assert.False(t, status.AAA)
+
+	editor := findCheck(&report, "editor.foreground", "editor.background")
+	require.NotNil(t, editor)
+	assert.True(t, editor.AAA)
+	assert.Equal(t, 21.0, editor.Ratio)
+	assert.Greater(t, editor.APCA, 100.0)
+
+	cursor := findCheck(&report, "editor.cursor", "editor.background")
+	require.NotNil(t, cursor)
+	assert.Equal(t, "#7F7F7F", cursor.ForegroundColor, "translucent colors are composited")
+	assert.Equal(t, contrast.KindUI, cursor.Kind)
+	assert.True(t, cursor.AA)
+
+	token := findCheck(&report, "$.token_colors[0].settings.foreground", "editor.background")
+	require.NotNil(t, token)
+	assert.False(t, token.AA)
+
+	assert.Equal(t, len(report.Checks), report.Summary.Total)
+	assert.Contains(t, report.Skipped, "foreground on background")
+}
+
+func TestContrastReportForBuiltin(t *testing.T) {
+	svc := newTestService(t)
+	report, err := svc.ContrastReport("orchestra-dark", contrast.Options{})
+	require.NoError(t, err)
+	assert.Empty(t, report.Skipped)
+	assert.Equal(t, len(contrast.Pairs), report.Summary.Total)
+
+	editor := findCheck(report, "editor.foreground", "editor.background")
+	require.NotNil(t, editor)
+	assert.True(t, editor.AA)
+	assert.Zero(t, editor.APCA, "APCA is opt-in")
+
+	_, err = svc.ContrastReport("missing", contrast.Options{})
+	assert.ErrorIs(t, err, service.ErrThemeNotFound)
+}
+
+func lowContrastTheme(id string) *types.ThemeDef {
+	return &types.ThemeDef{
+		ID:   id,
+		Name: "Low",
+		Type: "dark",
+		Colors: map[string]string{
+			"background":           "#1E1E1E",
+			"foreground":           "#D4D4D4",
+			"editor.background":    "#1E1E1E",
+			"editor.foreground":    "#5A5A5A",
+			"statusbar.background": "#2563EB",
+			"statusbar.foreground": "#3B82F6",
+		},
+		TokenColors: []types.TokenColor{
+			{Scope: []string{"comment"}, Settings: map[string]string{"foreground": "#3A5F3A", "fontStyle": "italic"}},
+			{Scope: []string{"string"}, Settings: map[string]string{"foreground": "#CE9178"}},
+		},
+	}
+}
+
+func TestRepairPreservesHue(t *testing.T) {
+	theme := lowContrastTheme("low")
+	repaired, fixes := contrast.Repair(theme, contrast.LevelAA)
+
+	var status *contrast.Fix
+	for i := range fixes {
+		if fixes[i].Target == "statusbar.foreground" {
+			status = &fixes[i]
+		}
+	}
+	require.NotNil(t, status)
+	assert.True(t, status.Met)
+	assert.Less(t, status.Before, 4.5)
+	assert.GreaterOrEqual(t, status.After, 4.5)
+	assert.Equal(t, status.To, repaired.Colors["statusbar.foreground"])
+	assert.Equal(t, "#3B82F6", theme.Colors["statusbar.foreground"], "input is not modified")
+
+	_, _, h1 := color.MustParse(status.From).OKLCH()
+	_, _, h2 := color.MustParse(status.To).OKLCH()
+	assert.InDelta(t, h1, h2, 3)
+
+	assert.Equal(t, "#CE9178", repaired.TokenColors[1].Settings["foreground"], "passing colors are kept")
+	assert.NotEqual(t, "#3A5F3A", repaired.TokenColors[0].Settings["foreground"])
+	assert.Equal(t, "italic", repaired.TokenColors[0].Settings["fontStyle"])
+
+	report := contrast.Audit(repaired, contrast.Options{})
+	for _, c := range report.Checks {
+		assert.True(t, c.AA, "%s on %s: %.2f", c.Foreground, c.Background, c.Ratio)
+	}
+}
+
+func TestRepairUnreachable(t *testing.T) {
+	theme := &types.ThemeDef{
+		ID: "gray",
+		Colors: map[string]string{
+			"editor.background": "#777777",
+			"editor.foreground": "#888888",
+		},
+	}
+	_, fixes := contrast.Repair(theme, contrast.LevelAAA)
+	require.Len(t, fixes, 1)
+	assert.False(t, fixes[0].Met)
+	assert.Greater(t, fixes[0].After, fixes[0].Before)
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := contrast.ParseLevel("")
+	require.NoError(t, err)
+	assert.Equal(t, contrast.LevelAA, level)
+	level, err = contrast.ParseLevel("aaa")
+	require.NoError(t, err)
+	assert.Equal(t, contrast.LevelAAA, level)
+	_, err = contrast.ParseLevel("A")
+	assert.Error(t, err)
+}
+
+func TestRepairContrastPatch(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(lowContrastTheme("low")))
+
+	result, err := svc.RepairContrast("low", service.RepairOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, contrast.LevelAA, result.Level)
+	assert.NotEmpty(t, result.Fixes)
+	assert.Nil(t, result.Theme)
+	require.NotNil(t, result.Patch)
+
+	data, err := json.Marshal(result.Patch)
+	require.NoError(t, err)
+	_, err = svc.PatchTheme("low", data)
+	require.NoError(t, err)
+
+	report, err := svc.ContrastReport("low", contrast.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, report.Summary.Total, report.Summary.PassAA)
+
+	again, err := svc.RepairContrast("low", service.RepairOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, again.Fixes)
+	assert.Nil(t, again.Patch)
+}
+
+func TestRepairContrastDerivedTheme(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(lowContrastTheme("low")))
+
+	result, err := svc.RepairContrast("low", service.RepairOptions{Level: "AAA", NewID: "low-aaa"})
+	require.NoError(t, err)
+	require.NotNil(t, result.Theme)
+	assert.Equal(t, "low-aaa", result.Theme.ID)
+	assert.Equal(t, "Low (AAA)", result.Theme.Name)
+
+	raw, err := svc.GetRawTheme("low-aaa")
+	require.NoError(t, err)
+	assert.Equal(t, "low", raw.Extends)
+
+	// White is the best statusbar.foreground on this blue, and still
+	// short of 7:1.
+	var unmet []string
+	for _, f := range result.Fixes {
+		if !f.Met {
+			unmet = append(unmet, f.Target)
+		}
+	}
+	assert.Equal(t, []string{"statusbar.foreground"}, unmet)
+
+	report, err := svc.ContrastReport("low-aaa", contrast.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, report.Summary.Total-1, report.Summary.PassAAA)
+	assert.Equal(t, len(result.Theme.TokenColors)-2, report.Summary.Total-len(contrast.Pairs),
+		"base token rules overridden by the repair are not audited")
+
+	source, err := svc.GetRawTheme("low")
+	require.NoError(t, err)
+	assert.Equal(t, "#3B82F6", source.Colors["statusbar.foreground"], "source theme is untouched")
+}
+
+func TestRepairContrastErrors(t *testing.T) {
+	svc := newTestService(t)
+	_, err := svc.RepairContrast("missing", service.RepairOptions{})
+	assert.ErrorIs(t, err, service.ErrThemeNotFound)
+
+	_, err = svc.RepairContrast("orchestra-dark", service.RepairOptions{Level: "AAAA"})
+	assert.Error(t, err)
+
+	_, err = svc.RepairContrast("orchestra-dark", service.RepairOptions{NewID: "orchestra-light"})
+	assert.ErrorIs(t, err, service.ErrBuiltinTheme)
+}
+
+func TestRepairContrastNewIDConflict(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(lowContrastTheme("low")))
+	registerCustom(t, svc, "mine")
+
+	_, err := svc.RepairContrast("low", service.RepairOptions{NewID: "mine"})
+	require.ErrorIs(t, err, service.ErrThemeExists)
+	mine, err := svc.GetRawTheme("mine")
+	require.NoError(t, err)
+	assert.Empty(t, mine.Extends, "the existing theme is not replaced")
+	assert.Equal(t, "#000000", mine.Colors["background"])
+}
+
+func TestRepairContrastInPlace(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(lowContrastTheme("low")))
+
+	result, err := svc.RepairContrast("low", service.RepairOptions{NewID: "low"})
+	require.NoError(t, err)
+	require.NotNil(t, result.Theme)
+	assert.Equal(t, "low", result.Theme.ID)
+	assert.Empty(t, result.Theme.Extends)
+
+	report, err := svc.ContrastReport("low", contrast.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, report.Summary.Total, report.Summary.PassAA)
+}
@@ -0,0 +1,243 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/orchestra-mcp/themes/src/builtin"
+	"github.com/orchestra-mcp/themes/src/exporter"
+	"github.com/orchestra-mcp/themes/src/generator"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- VS Code Export ---
+
+func TestExportVSCodeRoundTrip(t *testing.T) {
+	original, err := importer.ImportVSCode(sampleVSCodeTheme)
+	require.NoError(t, err)
+
+	data, err := exporter.ExportVSCode(original)
+	require.NoError(t, err)
+	assert.Equal(t, importer.FormatVSCodeJSON, importer.DetectFormat(data))
+
+	again, err := importer.ImportVSCode(data)
+	require.NoError(t, err)
+	assert.Equal(t, original, again)
+}
+
+func TestExportVSCodeRestoresRawKeys(t *testing.T) {
+	theme, err := importer.ImportVSCode(sampleVSCodeTheme)
+	require.NoError(t, err)
+
+	data, err := exporter.ExportVSCode(theme)
+	require.NoError(t, err)
+
+	var file struct {
+		Name        string            `json:"name"`
+		Type        string            `json:"type"`
+		Colors      map[string]string `json:"colors"`
+		TokenColors []struct {
+			Scope any `json:"scope"`
+		} `json:"tokenColors"`
+	}
+	require.NoError(t, json.Unmarshal(data, &file))
+	assert.Equal(t, "One Dark Pro", file.Name)
+	assert.Equal(t, "dark", file.Type)
+	assert.Equal(t, "#282c34", file.Colors["tab.activeBackground"], "unmapped key restored verbatim")
+	assert.Equal(t, "#282c34", file.Colors["editor.background"], "original spelling kept")
+	assert.NotContains(t, file.Colors, "background", "canonical-only keys are not emitted")
+	assert.Equal(t, "comment", file.TokenColors[0].Scope)
+	assert.Equal(t, []any{"string.quoted.double", "string.quoted.single"}, file.TokenColors[1].Scope)
+}
+
+func TestExportVSCodeCanonicalEditsWin(t *testing.T) {
+	theme, err := importer.ImportVSCode(sampleVSCodeTheme)
+	require.NoError(t, err)
+	theme.Colors["editor.background"] = "#000000"
+
+	data, err := exporter.ExportVSCode(theme)
+	require.NoError(t, err)
+	again, err := importer.ImportVSCode(data)
+	require.NoError(t, err)
+	assert.Equal(t, "#000000", again.Colors["editor.background"])
+}
+
+func TestExportVSCodeFromTmTheme(t *testing.T) {
+	original, err := importer.ImportTmTheme(sampleTmTheme)
+	require.NoError(t, err)
+
+	data, err := exporter.ExportVSCode(original)
+	require.NoError(t, err)
+	again, err := importer.ImportVSCode(data)
+	require.NoError(t, err)
+
+	for _, key := range []string{"editor.background", "editor.foreground", "editor.cursor", "editor.selection", "editor.lineHighlight"} {
+		assert.Equal(t, original.Colors[key], again.Colors[key], key)
+	}
+	assert.NotContains(t, again.Colors, "raw.caret", "tmTheme raw keys are not VS Code keys")
+	assert.Equal(t, original.TokenColors, again.TokenColors)
+}
+
+func TestExportVSCodeBuiltin(t *testing.T) {
+	data, err := exporter.ExportVSCode(builtin.LightTheme())
+	require.NoError(t, err)
+
+	again, err := importer.ImportVSCode(data)
+	require.NoError(t, err)
+	assert.Equal(t, "light", again.Type)
+	assert.Equal(t, "Orchestra Light", again.Name)
+	for vsKey, canonical := range importer.VSCodeColorMap() {
+		assert.Equal(t, builtin.LightTheme().Colors[canonical[0]], again.Colors[canonical[0]], vsKey)
+	}
+}
+
+func TestExportThemeFormats(t *testing.T) {
+	svc := newTestService(t)
+
+	data, err := svc.ExportTheme("orchestra-dark", service.ExportOptions{Format: exporter.FormatVSCode})
+	require.NoError(t, err)
+	assert.Equal(t, importer.FormatVSCodeJSON, importer.DetectFormat(data))
+
+	data, err = svc.ExportTheme("orchestra-dark", service.ExportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, importer.FormatOrchestraJSON, importer.DetectFormat(data))
+
+	_, err = svc.ExportTheme("orchestra-dark", service.ExportOptions{Format: "emacs"})
+	assert.ErrorIs(t, err, exporter.ErrUnsupportedFormat)
+}
+
+// --- tmTheme Export ---
+
+func TestExportTmThemeRoundTrip(t *testing.T) {
+	original, err := importer.ImportTmTheme(sampleTmTheme)
+	require.NoError(t, err)
+
+	data, err := exporter.ExportTmTheme(original)
+	require.NoError(t, err)
+	assert.Equal(t, importer.FormatTmTheme, importer.DetectFormat(data))
+
+	again, err := importer.ImportTmTheme(data)
+	require.NoError(t, err)
+	assert.Equal(t, original, again)
+}
+
+func TestExportTmThemeStructure(t *testing.T) {
+	theme, err := importer.ImportVSCode(sampleVSCodeTheme)
+	require.NoError(t, err)
+	theme.Name = "Dark & <Bright>"
+	theme.Colors["editor.cursor"] = "#528BFF"
+
+	data, err := exporter.ExportTmTheme(theme)
+	require.NoError(t, err)
+	out := string(data)
+
+	assert.Contains(t, out, "<string>Dark &amp; &lt;Bright&gt;</string>")
+	assert.Contains(t, out, "<key>background</key>\n\t\t\t\t<string>#282C34</string>")
+	assert.Contains(t, out, "<key>caret</key>\n\t\t\t\t<string>#528BFF</string>")
+	assert.NotContains(t, out, "tab.activeBackground", "VS Code raw keys are not tmTheme settings")
+	assert.Contains(t, out, "<string>string.quoted.double, string.quoted.single</string>")
+
+	again, err := importer.ImportTmTheme(data)
+	require.NoError(t, err)
+	assert.Equal(t, theme.Name, again.Name)
+	assert.Equal(t, "#528BFF", again.Colors["editor.cursor"])
+	assert.Equal(t, theme.TokenColors, again.TokenColors)
+}
+
+func TestExportTmThemeBuiltin(t *testing.T) {
+	svc := newTestService(t)
+	data, err := svc.ExportTheme("orchestra-light", service.ExportOptions{Format: exporter.FormatTmTheme})
+	require.NoError(t, err)
+
+	again, err := importer.ImportTmTheme(data)
+	require.NoError(t, err)
+	light := builtin.LightTheme()
+	assert.Equal(t, "light", again.Type)
+	assert.Equal(t, "Orchestra Team", again.Author)
+	for _, key := range []string{"editor.background", "editor.foreground", "editor.cursor", "editor.selection", "editor.lineHighlight"} {
+		assert.Equal(t, light.Colors[key], again.Colors[key], key)
+	}
+	assert.Equal(t, "application/xml", exporter.ContentType(exporter.FormatTmTheme))
+}
+
+// --- CSS Export ---
+
+func TestCSSPropertyNames(t *testing.T) {
+	assert.Equal(t, "--orchestra-background", exporter.CSSProperty("", "background"))
+	assert.Equal(t, "--orchestra-editor-line-highlight", exporter.CSSProperty("", "editor.lineHighlight"))
+	assert.Equal(t, "--app-focus-border", exporter.CSSProperty("app", "focus.border"))
+	assert.Equal(t, "--orchestra-background", exporter.CSSProperty("x;}body{", "background"), "invalid prefixes fall back to the default")
+
+	assert.True(t, exporter.ValidCSSPrefix(""))
+	assert.True(t, exporter.ValidCSSPrefix("my_app-2"))
+	for _, prefix := range []string{"x;}", "a b", "a:b", `a"`, "a/*"} {
+		assert.False(t, exporter.ValidCSSPrefix(prefix), prefix)
+	}
+}
+
+func TestExportCSS(t *testing.T) {
+	theme := builtin.DarkTheme()
+	theme.Colors["editor.background"] = "red; } body { display: none"
+
+	css := string(exporter.ExportCSS(theme, exporter.CSSOptions{}))
+	assert.True(t, strings.HasPrefix(css, ":root {\n  color-scheme: dark;\n"), css)
+	assert.Contains(t, css, "  --orchestra-statusbar-background: "+theme.Colors["statusbar.background"]+";\n")
+	assert.NotContains(t, css, "--orchestra-editor-background", "invalid values are dropped")
+	assert.NotContains(t, css, "display")
+
+	scoped := string(exporter.ExportCSS(theme, exporter.CSSOptions{Scope: exporter.ScopeTheme, Prefix: "app"}))
+	assert.True(t, strings.HasPrefix(scoped, `[data-theme="orchestra-dark"] {`), scoped)
+	assert.Contains(t, scoped, "--app-background: ")
+}
+
+func TestStylesheet(t *testing.T) {
+	svc := newTestService(t)
+	css := string(svc.Stylesheet(""))
+
+	assert.Contains(t, css, `[data-theme="orchestra-dark"] {`)
+	assert.Contains(t, css, `[data-theme="orchestra-light"] {`)
+	assert.Less(t, strings.Index(css, "orchestra-dark"), strings.Index(css, "orchestra-light"), "themes are sorted by ID")
+	assert.Contains(t, css, "@media (prefers-color-scheme: light) {\n  :root {\n    color-scheme: light;\n")
+	assert.Contains(t, css, "@media (prefers-color-scheme: dark) {\n  :root {\n    color-scheme: dark;\n")
+	assert.Less(t, strings.LastIndex(css, "@media"), strings.Index(css, "[data-theme="),
+		"theme rules follow the defaults so an explicit data-theme wins on :root")
+
+	_, err := svc.GenerateTheme(generator.Seeds{ID: "paper", Background: "#FFFBF0", Foreground: "#222222", Accent: "#B5542E"})
+	require.NoError(t, err)
+	require.NoError(t, svc.SetActiveTheme("paper"))
+
+	css = string(svc.Stylesheet("app"))
+	light := css[strings.Index(css, "prefers-color-scheme: light"):strings.Index(css, "prefers-color-scheme: dark")]
+	assert.Contains(t, light, "--app-background: #FFFBF0;", "active light theme answers the light scheme")
+	assert.Contains(t, css, `[data-theme="paper"] {`)
+}
+
+func TestExportThemeCSSFormat(t *testing.T) {
+	svc := newTestService(t)
+	data, err := svc.ExportTheme("orchestra-light", service.ExportOptions{
+		Format: exporter.FormatCSS,
+		CSS:    exporter.CSSOptions{Scope: exporter.ScopeTheme},
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), `[data-theme="orchestra-light"] {`))
+	assert.Equal(t, "text/css; charset=utf-8", exporter.ContentType(exporter.FormatCSS))
+}
+
+func TestExportVSCodeSemanticRoundTrip(t *testing.T) {
+	original, err := importer.ImportVSCode(sampleSemanticTheme)
+	require.NoError(t, err)
+
+	data, err := exporter.ExportVSCode(original)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"variable.readonly": "#4FC1FF"`)
+
+	again, err := importer.ImportVSCode(data)
+	require.NoError(t, err)
+	assert.Equal(t, original.SemanticHighlighting, again.SemanticHighlighting)
+	assert.Equal(t, original.SemanticTokenColors, again.SemanticTokenColors)
+	assert.Equal(t, original.TokenColors, again.TokenColors)
+}
@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/orchestra-mcp/themes/src/service"
+	"github.com/orchestra-mcp/themes/src/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtendsBuiltinTheme(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:      "dark-purple",
+		Name:    "Dark Purple",
+		Extends: "orchestra-dark",
+		Colors:  map[string]string{"accent": "#AA00FF"},
+	}))
+
+	got, err := svc.GetTheme("dark-purple")
+	require.NoError(t, err)
+	assert.Equal(t, "dark-purple", got.ID)
+	assert.Equal(t, "Dark Purple", got.Name)
+	assert.Equal(t, "dark", got.Type, "type is inherited")
+	assert.Equal(t, "#AA00FF", got.Colors["accent"], "own color wins")
+	assert.Equal(t, "#1E1E2E", got.Colors["background"], "base color inherited")
+
+	raw, err := svc.GetRawTheme("dark-purple")
+	require.NoError(t, err)
+	assert.Len(t, raw.Colors, 1)
+
+	base, _ := svc.GetTheme("orchestra-dark")
+	assert.Equal(t, "#CBA6F7", base.Colors["accent"], "base must not be mutated")
+}
+
+func TestGettersReturnCopies(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID: "plain", Name: "Plain", Type: "dark",
+		Colors: map[string]string{"background": "#000000"},
+		TokenColors: []types.TokenColor{
+			{Scope: []string{"comment"}, Settings: map[string]string{"foreground": "#888888"}},
+		},
+	}))
+
+	view, err := svc.GetTheme("plain")
+	require.NoError(t, err)
+	view.Colors["background"] = "#FF0000"
+	view.TokenColors[0].Settings["foreground"] = "#FF0000"
+	raw, err := svc.GetRawTheme("plain")
+	require.NoError(t, err)
+	raw.Colors["background"] = "#FF0000"
+	raw.TokenColors[0].Scope[0] = "string"
+
+	raw, err = svc.GetRawTheme("plain")
+	require.NoError(t, err)
+	assert.Equal(t, "#000000", raw.Colors["background"])
+	assert.Equal(t, []string{"comment"}, raw.TokenColors[0].Scope)
+	view, err = svc.GetTheme("plain")
+	require.NoError(t, err)
+	assert.Equal(t, "#000000", view.Colors["background"])
+	assert.Equal(t, "#888888", view.TokenColors[0].Settings["foreground"])
+}
+
+func TestExtendsCanDisableSemanticHighlighting(t *testing.T) {
+	svc := newTestService(t)
+	on, off := true, false
+	base := &types.ThemeDef{
+		ID: "semantic-base", Name: "Base", Type: "dark",
+		SemanticHighlighting: &on,
+		Colors:               map[string]string{"background": "#000000"},
+	}
+	require.NoError(t, svc.RegisterTheme(base))
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID: "inherits", Name: "Inherits", Extends: "semantic-base",
+		Colors: map[string]string{},
+	}))
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID: "disables", Name: "Disables", Extends: "semantic-base",
+		SemanticHighlighting: &off,
+		Colors:               map[string]string{},
+	}))
+
+	inherits, err := svc.GetTheme("inherits")
+	require.NoError(t, err)
+	require.NotNil(t, inherits.SemanticHighlighting)
+	assert.True(t, *inherits.SemanticHighlighting)
+
+	disables, err := svc.GetTheme("disables")
+	require.NoError(t, err)
+	require.NotNil(t, disables.SemanticHighlighting)
+	assert.False(t, *disables.SemanticHighlighting)
+}
+
+func TestExtendsMultiLevelChain(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:      "mid",
+		Name:    "Mid",
+		Extends: "orchestra-light",
+		Colors:  map[string]string{"primary": "#111111", "accent": "#222222"},
+		TokenColors: []types.TokenColor{
+			{Scope: []string{"comment"}, Settings: map[string]string{"foreground": "#999999"}},
+		},
+	}))
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:      "leaf",
+		Name:    "Leaf",
+		Extends: "mid",
+		Colors:  map[string]string{"accent": "#333333"},
+		TokenColors: []types.TokenColor{
+			{Scope: []string{"comment"}, Settings: map[string]string{"foreground": "#777777"}},
+		},
+	}))
+
+	got, err := svc.GetTheme("leaf")
+	require.NoError(t, err)
+	assert.Equal(t, "#333333", got.Colors["accent"])
+	assert.Equal(t, "#111111", got.Colors["primary"])
+	assert.Equal(t, "#FFFFFF", got.Colors["background"])
+	require.Len(t, got.TokenColors, 2)
+	assert.Equal(t, "#777777", got.TokenColors[1].Settings["foreground"], "derived rules come last")
+}
+
+func TestExtendsRejectsMissingBase(t *testing.T) {
+	svc := newTestService(t)
+	err := svc.RegisterTheme(&types.ThemeDef{ID: "orphan", Extends: "nope"})
+	assert.True(t, errors.Is(err, service.ErrInvalidExtends), "got %v", err)
+}
+
+func TestExtendsRejectsCycles(t *testing.T) {
+	svc := newTestService(t)
+
+	err := svc.RegisterTheme(&types.ThemeDef{ID: "self", Extends: "self"})
+	assert.True(t, errors.Is(err, service.ErrInvalidExtends), "got %v", err)
+
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{ID: "a", Extends: "orchestra-dark"}))
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{ID: "b", Extends: "a"}))
+	_, err = svc.UpdateTheme("a", &types.ThemeDef{Extends: "b"})
+	assert.True(t, errors.Is(err, service.ErrInvalidExtends), "got %v", err)
+}
+
+func TestExtendsReResolvesOnBaseUpdate(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:     "base",
+		Type:   "dark",
+		Colors: map[string]string{"background": "#000000"},
+	}))
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{ID: "child", Extends: "base"}))
+	require.NoError(t, svc.SetActiveTheme("child"))
+
+	var newBg string
+	svc.OnDidChangeTheme(func(_, new *types.ThemeDef) {
+		newBg = new.Colors["background"]
+	})
+
+	_, err := svc.PatchTheme("base", []byte(`{"colors":{"background":"#101010"}}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "#101010", newBg, "listeners see the re-resolved active theme")
+	assert.Equal(t, "#101010", svc.GetActiveTheme().Colors["background"])
+}
+
+func TestExportThemeResolvedAndRaw(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:      "delta",
+		Extends: "orchestra-light",
+		Colors:  map[string]string{"accent": "#123456"},
+	}))
+
+	data, err := svc.ExportTheme("delta", service.ExportOptions{})
+	require.NoError(t, err)
+	var resolved types.ThemeDef
+	require.NoError(t, json.Unmarshal(data, &resolved))
+	assert.Equal(t, "#FFFFFF", resolved.Colors["background"])
+
+	data, err = svc.ExportTheme("delta", service.ExportOptions{Raw: true})
+	require.NoError(t, err)
+	var raw types.ThemeDef
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, "orchestra-light", raw.Extends)
+	assert.Len(t, raw.Colors, 1)
+}
+
+func TestExtendsBrokenChainFallsBackToRaw(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{ID: "parent", Colors: map[string]string{"a": "#000000"}}))
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{ID: "kid", Extends: "parent", Colors: map[string]string{"b": "#FFFFFF"}}))
+	require.NoError(t, svc.DeleteTheme("parent"))
+
+	got, err := svc.GetTheme("kid")
+	require.NoError(t, err)
+	assert.Equal(t, "#FFFFFF", got.Colors["b"])
+	assert.NotContains(t, got.Colors, "a", "base colors cannot be inherited")
+}
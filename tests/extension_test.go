@@ -0,0 +1,269 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeZip builds an in-memory zip archive from files.
+func makeZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+var sampleVSIXFiles = map[string][]byte{
+	"extension.vsixmanifest": []byte(`<?xml version="1.0"?><PackageManifest/>`),
+	"extension/package.json": []byte(`{
+		"name": "midnight-themes",
+		"displayName": "%displayName%",
+		"publisher": "acme",
+		"version": "1.2.0",
+		"contributes": {
+			"themes": [
+				{ "label": "Midnight", "uiTheme": "vs-dark", "path": "./themes/midnight-color-theme.json" },
+				{ "label": "%theme.noon%", "uiTheme": "vs", "path": "./themes/Noon.tmTheme" },
+				{ "label": "Midnight HC", "uiTheme": "hc-black", "path": "./themes/hc.json" },
+				{ "label": "Broken", "uiTheme": "vs-dark", "path": "./themes/missing.json" }
+			]
+		}
+	}`),
+	"extension/package.nls.json": []byte(`{
+		"displayName": "Midnight Themes",
+		"theme.noon": { "message": "Noon", "comment": ["light variant"] }
+	}`),
+	"extension/themes/midnight-color-theme.json": []byte(`{
+		"name": "midnight (file name)",
+		"include": "./base.json",
+		"colors": { "editor.background": "#0B0E14" },
+		"tokenColors": []
+	}`),
+	"extension/themes/base.json": []byte(`{
+		"colors": { "editor.foreground": "#BFBDB6" },
+		"tokenColors": [
+			{ "scope": "comment", "settings": { "foreground": "#5C6773" } }
+		]
+	}`),
+	"extension/themes/hc.json": []byte(`{
+		"colors": { "editor.background": "#000000", "editor.foreground": "#FFFFFF" },
+		"tokenColors": []
+	}`),
+	"extension/themes/Noon.tmTheme": sampleTmTheme,
+}
+
+func TestImportVSIX(t *testing.T) {
+	ext, err := importer.ImportVSIX(makeZip(t, sampleVSIXFiles))
+	require.NoError(t, err)
+
+	assert.Equal(t, "midnight-themes", ext.Name)
+	assert.Equal(t, "acme", ext.Publisher)
+	assert.Equal(t, "Midnight Themes", ext.DisplayName)
+	assert.Equal(t, "1.2.0", ext.Version)
+	require.Len(t, ext.Themes, 4)
+
+	midnight := ext.Themes[0]
+	require.NoError(t, midnight.Err)
+	assert.Equal(t, "midnight", midnight.Theme.ID)
+	assert.Equal(t, "Midnight", midnight.Theme.Name)
+	assert.Equal(t, "dark", midnight.Theme.Type)
+	assert.Equal(t, "acme", midnight.Theme.Author)
+	assert.Equal(t, "Midnight Themes", midnight.Theme.Description)
+	assert.Equal(t, "#BFBDB6", midnight.Theme.Colors["editor.foreground"], "include is resolved")
+	assert.Len(t, midnight.Theme.TokenColors, 1)
+
+	noon := ext.Themes[1]
+	require.NoError(t, noon.Err)
+	assert.Equal(t, "Noon", noon.Label)
+	assert.Equal(t, "noon", noon.Theme.ID)
+	assert.Equal(t, "light", noon.Theme.Type)
+	assert.Equal(t, "tmtheme", noon.Theme.Source)
+
+	assert.Equal(t, "high-contrast", ext.Themes[2].Theme.Type)
+
+	broken := ext.Themes[3]
+	assert.Nil(t, broken.Theme)
+	require.Error(t, broken.Err)
+	assert.Contains(t, broken.Err.Error(), "not found")
+}
+
+func TestImportVSIXWithoutThemes(t *testing.T) {
+	data := makeZip(t, map[string][]byte{
+		"extension/package.json": []byte(`{"name": "snippets", "contributes": {}}`),
+	})
+	_, err := importer.ImportVSIX(data)
+	assert.ErrorIs(t, err, importer.ErrInvalidExtension)
+
+	_, err = importer.ImportVSIX(makeZip(t, map[string][]byte{"readme.md": []byte("#")}))
+	assert.ErrorIs(t, err, importer.ErrInvalidExtension)
+}
+
+func TestServiceImportVSIX(t *testing.T) {
+	svc := newTestService(t)
+	result, err := svc.ImportVSIX(makeZip(t, sampleVSIXFiles), service.DiscoverOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "acme.midnight-themes", result.Extension)
+	assert.Equal(t, 3, result.Imported)
+	require.Len(t, result.Themes, 4)
+	assert.Equal(t, "midnight", result.Themes[0].ID)
+	require.NotNil(t, result.Themes[0].Theme)
+	assert.Empty(t, result.Themes[0].Error)
+	assert.NotEmpty(t, result.Themes[3].Error)
+
+	theme, err := svc.GetTheme("noon")
+	require.NoError(t, err)
+	assert.Equal(t, "Wimer Hazenberg", theme.Author, "the theme file's author is kept")
+	assert.Equal(t, "Midnight Themes", theme.Description)
+
+	again, err := svc.ImportVSIX(makeZip(t, sampleVSIXFiles), service.DiscoverOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, again.Imported)
+	assert.Equal(t, 3, again.Skipped)
+	assert.Equal(t, "already registered", again.Themes[0].Skipped)
+
+	overwrite, err := svc.ImportVSIX(makeZip(t, sampleVSIXFiles), service.DiscoverOptions{Overwrite: true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, overwrite.Imported)
+}
+
+func TestServiceImportVSIXKeepsUserThemes(t *testing.T) {
+	svc := newTestService(t)
+	registerCustom(t, svc, "midnight")
+
+	result, err := svc.ImportVSIX(makeZip(t, sampleVSIXFiles), service.DiscoverOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, "midnight", result.Themes[0].ID)
+	assert.Equal(t, "already registered", result.Themes[0].Skipped)
+	assert.Nil(t, result.Themes[0].Theme)
+
+	mine, err := svc.GetTheme("midnight")
+	require.NoError(t, err)
+	assert.Equal(t, "#000000", mine.Colors["background"], "the user theme is not replaced")
+}
+
+// writeExtension unpacks the "extension/" files of a .vsix layout into
+// dir/name.
+func writeExtension(t *testing.T, dir, name string, files map[string][]byte) {
+	t.Helper()
+	for file, data := range files {
+		rel, ok := strings.CutPrefix(file, "extension/")
+		if !ok {
+			continue
+		}
+		target := filepath.Join(dir, name, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(target), 0o755))
+		require.NoError(t, os.WriteFile(target, data, 0o644))
+	}
+}
+
+func TestDiscoverVSCodeThemes(t *testing.T) {
+	dir := t.TempDir()
+	writeExtension(t, dir, "acme.midnight-themes-1.2.0", sampleVSIXFiles)
+	writeExtension(t, dir, "acme.midnight-themes-1.1.0", sampleVSIXFiles)
+	writeExtension(t, dir, "acme.linter-2.0.0", map[string][]byte{
+		"extension/package.json":                []byte(`{"name": "linter", "contributes": {"commands": []}}`),
+		"extension/node_modules/x/package.json": []byte(`{}`),
+	})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".obsolete"),
+		[]byte(`{"acme.midnight-themes-1.1.0": true}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "extensions.json"), []byte(`[]`), 0o644))
+
+	svc := newTestService(t)
+	result, err := svc.DiscoverVSCodeThemes(dir, service.DiscoverOptions{})
+	require.NoError(t, err)
+
+	var added []string
+	for _, d := range result.Added {
+		assert.Equal(t, "acme.midnight-themes-1.2.0", d.Extension)
+		added = append(added, d.ID)
+	}
+	assert.ElementsMatch(t, []string{"midnight", "noon", "midnight-hc"}, added)
+	assert.Empty(t, result.Skipped)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "Broken", result.Failed[0].Label)
+
+	theme, err := svc.GetTheme("midnight")
+	require.NoError(t, err)
+	assert.Equal(t, "#0B0E14", theme.Colors["editor.background"])
+
+	again, err := svc.DiscoverVSCodeThemes(dir, service.DiscoverOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, again.Added)
+	assert.Len(t, again.Skipped, 3)
+	assert.Equal(t, "already registered", again.Skipped[0].Reason)
+
+	overwrite, err := svc.DiscoverVSCodeThemes(dir, service.DiscoverOptions{Overwrite: true})
+	require.NoError(t, err)
+	assert.Len(t, overwrite.Added, 3)
+}
+
+func TestDiscoverVSCodeThemesPrefersHighestVersion(t *testing.T) {
+	dir := t.TempDir()
+	extension := func(bg string) map[string][]byte {
+		return map[string][]byte{
+			"extension/package.json": []byte(`{"name": "dusk", "publisher": "acme", "contributes": {"themes": [
+				{"label": "Dusk", "uiTheme": "vs-dark", "path": "./dusk.json"}]}}`),
+			"extension/dusk.json": []byte(`{"colors": {"editor.background": "` + bg + `"}, "tokenColors": []}`),
+		}
+	}
+	writeExtension(t, dir, "acme.dusk-1.10.0", extension("#101010"))
+	writeExtension(t, dir, "acme.dusk-1.2.0", extension("#121212"))
+	writeExtension(t, dir, "acme.dusk-1.9.0-darwin-arm64", extension("#191919"))
+
+	svc := newTestService(t)
+	result, err := svc.DiscoverVSCodeThemes(dir, service.DiscoverOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Added, 1)
+	assert.Equal(t, "acme.dusk-1.10.0", result.Added[0].Extension)
+	require.Len(t, result.Skipped, 2)
+	assert.ElementsMatch(t, []string{"acme.dusk-1.2.0", "acme.dusk-1.9.0-darwin-arm64"},
+		[]string{result.Skipped[0].Extension, result.Skipped[1].Extension})
+
+	theme, err := svc.GetTheme("dusk")
+	require.NoError(t, err)
+	assert.Equal(t, "#101010", theme.Colors["editor.background"])
+}
+
+func TestImportExtensionDirReadsOnlyThemeFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"extension/syntaxes/huge.tmLanguage.json": bytes.Repeat([]byte(" "), 9<<20),
+	}
+	for name, data := range sampleVSIXFiles {
+		files[name] = data
+	}
+	writeExtension(t, dir, "acme.midnight-themes-1.2.0", files)
+
+	ext, err := importer.ImportExtensionDir(filepath.Join(dir, "acme.midnight-themes-1.2.0"))
+	require.NoError(t, err, "files outside contributes.themes are not read")
+	require.Len(t, ext.Themes, 4)
+	require.NoError(t, ext.Themes[0].Err)
+	assert.Equal(t, "#BFBDB6", ext.Themes[0].Theme.Colors["editor.foreground"], "include chains are followed")
+	assert.Equal(t, "Midnight Themes", ext.DisplayName)
+	assert.Error(t, ext.Themes[3].Err)
+}
+
+func TestDiscoverVSCodeThemesMissingDir(t *testing.T) {
+	svc := newTestService(t)
+	_, err := svc.DiscoverVSCodeThemes(filepath.Join(t.TempDir(), "missing"), service.DiscoverOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/contrast"
+	"github.com/orchestra-mcp/themes/src/generator"
+	"github.com/orchestra-mcp/themes/src/service"
+	"github.com/orchestra-mcp/themes/src/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCompleteTheme(t *testing.T) {
+	for _, seeds := range []generator.Seeds{
+		{ID: "gen-dark", Background: "#101418", Foreground: "#E6E6E6", Accent: "#FF8800"},
+		{ID: "gen-light", Background: "white", Foreground: "#222", Accent: "oklch(0.7 0.15 200)"},
+		{ID: "gen-muddy", Type: "dark", Background: "#404040", Foreground: "#707070", Accent: "#505050"},
+	} {
+		t.Run(seeds.ID, func(t *testing.T) {
+			theme, err := generator.Generate(seeds)
+			require.NoError(t, err)
+			assert.Equal(t, seeds.ID, theme.Name)
+			assert.Equal(t, "generated", theme.Source)
+			for _, key := range colorkeys.Names() {
+				assert.True(t, color.Valid(theme.Colors[key]), "missing or invalid %s", key)
+			}
+			assert.NotEmpty(t, theme.TokenColors)
+			assert.Empty(t, validator.Validate(theme).Errors())
+
+			report := contrast.Audit(theme, contrast.Options{})
+			assert.Empty(t, report.Skipped)
+			for _, c := range report.Checks {
+				assert.True(t, c.AA, "%s on %s: %.2f", c.Foreground, c.Background, c.Ratio)
+			}
+		})
+	}
+}
+
+func TestGenerateInfersType(t *testing.T) {
+	dark, err := generator.Generate(generator.Seeds{ID: "d", Background: "#000", Foreground: "#FFF", Accent: "#3B82F6"})
+	require.NoError(t, err)
+	assert.Equal(t, "dark", dark.Type)
+
+	light, err := generator.Generate(generator.Seeds{ID: "l", Background: "#FAFAFA", Foreground: "#111", Accent: "#3B82F6"})
+	require.NoError(t, err)
+	assert.Equal(t, "light", light.Type)
+	assert.Equal(t, "#FAFAFA", light.Colors[colorkeys.Background])
+	assert.Equal(t, light.Colors[colorkeys.Primary], light.Colors[colorkeys.StatusBarBackground])
+
+	_, _, seedHue := color.MustParse("#3B82F6").OKLCH()
+	_, _, hue := color.MustParse(light.Colors[colorkeys.Primary]).OKLCH()
+	assert.InDelta(t, seedHue, hue, 3, "accent hue is preserved")
+}
+
+func TestGenerateRejectsBadSeeds(t *testing.T) {
+	_, err := generator.Generate(generator.Seeds{ID: "x", Background: "#000", Foreground: "#FFF"})
+	assert.ErrorContains(t, err, "accent color is required")
+
+	_, err = generator.Generate(generator.Seeds{ID: "x", Background: "#00", Foreground: "#FFF", Accent: "#F00"})
+	assert.ErrorContains(t, err, "invalid background color")
+
+	_, err = generator.Generate(generator.Seeds{ID: "x", Type: "sepia", Background: "#000", Foreground: "#FFF", Accent: "#F00"})
+	assert.ErrorContains(t, err, "invalid theme type")
+}
+
+func TestGenerateThemeService(t *testing.T) {
+	svc := newTestService(t)
+	theme, err := svc.GenerateTheme(generator.Seeds{
+		ID: "ocean", Name: "Ocean", Background: "#0B1E2D", Foreground: "#D8E6F0", Accent: "#2EC4B6",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Ocean", theme.Name)
+	assert.Empty(t, theme.Synthesized)
+
+	stored, err := svc.GetRawTheme("ocean")
+	require.NoError(t, err)
+	assert.Equal(t, theme.Colors, stored.Colors)
+
+	_, err = svc.GenerateTheme(generator.Seeds{ID: "orchestra-dark", Background: "#000", Foreground: "#FFF", Accent: "#F00"})
+	assert.ErrorIs(t, err, service.ErrBuiltinTheme)
+
+	_, err = svc.GenerateTheme(generator.Seeds{ID: "ocean", Background: "#FFFFFF", Foreground: "#000000", Accent: "#F00"})
+	assert.ErrorIs(t, err, service.ErrThemeExists)
+	stored, err = svc.GetRawTheme("ocean")
+	require.NoError(t, err)
+	assert.Equal(t, "Ocean", stored.Name, "an existing theme is not replaced")
+
+	_, err = svc.GenerateTheme(generator.Seeds{ID: "bad", Background: "nope", Foreground: "#FFF", Accent: "#F00"})
+	assert.ErrorIs(t, err, service.ErrInvalidTheme)
+
+	_, err = svc.GenerateTheme(generator.Seeds{ID: "Bad ID", Background: "#000", Foreground: "#FFF", Accent: "#F00"})
+	assert.ErrorIs(t, err, service.ErrInvalidTheme)
+}
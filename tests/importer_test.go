@@ -98,14 +98,16 @@ func TestImportVSCodeColorMapping(t *testing.T) {
 	theme, err := importer.ImportVSCode(sampleVSCodeTheme)
 	require.NoError(t, err)
 
-	assert.Equal(t, "#282c34", theme.Colors["bg-primary"])
-	assert.Equal(t, "#abb2bf", theme.Colors["text-primary"])
-	assert.Equal(t, "#21252b", theme.Colors["bg-secondary"])
-	assert.Equal(t, "#2c313a", theme.Colors["bg-tertiary"])
-	assert.Equal(t, "#21252b", theme.Colors["bg-accent"])
-	assert.Equal(t, "#282c34", theme.Colors["bg-header"])
-	assert.Equal(t, "#528bff", theme.Colors["border-focus"])
-	assert.Equal(t, "#2c313a", theme.Colors["bg-selection"])
+	assert.Equal(t, "#282C34", theme.Colors["editor.background"])
+	assert.Equal(t, "#282C34", theme.Colors["background"])
+	assert.Equal(t, "#ABB2BF", theme.Colors["editor.foreground"])
+	assert.Equal(t, "#ABB2BF", theme.Colors["foreground"])
+	assert.Equal(t, "#21252B", theme.Colors["sidebar.background"])
+	assert.Equal(t, "#2C313A", theme.Colors["activitybar.background"])
+	assert.Equal(t, "#21252B", theme.Colors["statusbar.background"])
+	assert.Equal(t, "#282C34", theme.Colors["titlebar.background"])
+	assert.Equal(t, "#528BFF", theme.Colors["focus.border"])
+	assert.Equal(t, "#2C313A", theme.Colors["list.selection"])
 }
 
 func TestImportVSCodeRawColors(t *testing.T) {
@@ -124,13 +126,13 @@ func TestImportVSCodeTokenColors(t *testing.T) {
 
 	assert.Equal(t, "Comments", theme.TokenColors[0].Name)
 	assert.Equal(t, []string{"comment"}, theme.TokenColors[0].Scope)
-	assert.Equal(t, "#5c6370", theme.TokenColors[0].Settings["foreground"])
+	assert.Equal(t, "#5C6370", theme.TokenColors[0].Settings["foreground"])
 	assert.Equal(t, "italic", theme.TokenColors[0].Settings["fontStyle"])
 
 	assert.Equal(t, "Strings", theme.TokenColors[1].Name)
 	assert.Contains(t, theme.TokenColors[1].Scope, "string.quoted.double")
 	assert.Contains(t, theme.TokenColors[1].Scope, "string.quoted.single")
-	assert.Equal(t, "#98c379", theme.TokenColors[1].Settings["foreground"])
+	assert.Equal(t, "#98C379", theme.TokenColors[1].Settings["foreground"])
 }
 
 func TestImportVSCodeInclude(t *testing.T) {
@@ -142,9 +144,10 @@ func TestImportVSCodeInclude(t *testing.T) {
 		"tokenColors": []
 	}`)
 
-	theme, err := importer.ImportVSCode(data)
-	require.NoError(t, err)
-	assert.Equal(t, "./base-theme.json", theme.Colors["_include"])
+	_, err := importer.ImportVSCode(data)
+	require.ErrorIs(t, err, importer.ErrIncludeUnresolved)
+	assert.Contains(t, err.Error(), "./base-theme.json")
+	assert.Contains(t, err.Error(), "bundle")
 }
 
 func TestImportVSCodeInvalidJSON(t *testing.T) {
@@ -218,3 +221,251 @@ func TestVSCodeScopeAsCommaSeparated(t *testing.T) {
 	require.Len(t, theme.TokenColors, 1)
 	assert.Equal(t, []string{"keyword.control", "storage.type"}, theme.TokenColors[0].Scope)
 }
+
+// --- JSONC ---
+
+var sampleJSONCTheme = []byte("\xef\xbb\xbf" + `{
+	// Exported from the marketplace
+	"name": "Commented // Theme",
+	"type": "dark",
+	"colors": {
+		"editor.background": "#1e1e1e", /* main */
+		"editor.foreground": "#d4d4d4",
+	},
+	"tokenColors": [
+		{
+			"scope": "comment",
+			"settings": { "foreground": "#6a9955", }, // trailing comma
+		},
+	],
+}`)
+
+func TestDetectFormatJSONC(t *testing.T) {
+	assert.Equal(t, importer.FormatVSCodeJSON, importer.DetectFormat(sampleJSONCTheme))
+}
+
+func TestImportVSCodeJSONC(t *testing.T) {
+	theme, err := importer.Import(sampleJSONCTheme)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Commented // Theme", theme.Name, "comment markers inside strings are kept")
+	assert.Equal(t, "#1E1E1E", theme.Colors["editor.background"])
+	assert.Equal(t, "#D4D4D4", theme.Colors["editor.foreground"])
+	require.Len(t, theme.TokenColors, 1)
+	assert.Equal(t, "#6A9955", theme.TokenColors[0].Settings["foreground"])
+}
+
+func TestImportVSCodeErrorPosition(t *testing.T) {
+	data := []byte(`{
+	"name": "Broken",
+	"colors": {
+		"editor.background": "#000000"
+		"editor.foreground": "#ffffff"
+	},
+	"tokenColors": []
+}`)
+	assert.Equal(t, importer.FormatVSCodeJSON, importer.DetectFormat(data), "malformed VS Code themes are still detected")
+
+	_, err := importer.Import(data)
+	var syntaxErr *importer.SyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+	assert.Equal(t, 5, syntaxErr.Line)
+	assert.Equal(t, 3, syntaxErr.Column)
+	assert.Contains(t, err.Error(), "line 5, column 3")
+}
+
+func TestImportVSCodeUnterminatedComment(t *testing.T) {
+	_, err := importer.ImportVSCode([]byte("{\n  \"tokenColors\": [] /* oops\n}"))
+	var syntaxErr *importer.SyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+	assert.Equal(t, 2, syntaxErr.Line)
+	assert.Equal(t, 21, syntaxErr.Column)
+	assert.Contains(t, syntaxErr.Msg, "unterminated block comment")
+}
+
+// --- Semantic Tokens ---
+
+var sampleSemanticTheme = []byte(`{
+	"name": "Semantic",
+	"type": "dark",
+	"colors": {"editor.background": "#1e1e1e"},
+	"semanticHighlighting": true,
+	"semanticTokenColors": {
+		"variable.readonly": "#4fc1ff",
+		"*.deprecated": {"strikethrough": true},
+		"function.declaration.async:typescript": {"foreground": "#dcdcaa", "fontStyle": "italic bold", "underline": false},
+		"not a selector": "#ffffff",
+		"parameter": {"foreground": "nope"}
+	},
+	"tokenColors": [
+		{"scope": "markup.inserted", "settings": {"foreground": "#b5cea8", "background": "#203020"}}
+	]
+}`)
+
+func TestImportVSCodeTokenBackground(t *testing.T) {
+	theme, err := importer.ImportVSCode(sampleSemanticTheme)
+	require.NoError(t, err)
+	require.Len(t, theme.TokenColors, 1)
+	assert.Equal(t, "#203020", theme.TokenColors[0].Settings["background"])
+}
+
+func TestImportVSCodeSemanticTokenColors(t *testing.T) {
+	theme, err := importer.ImportVSCode(sampleSemanticTheme)
+	require.NoError(t, err)
+	require.NotNil(t, theme.SemanticHighlighting)
+	assert.True(t, *theme.SemanticHighlighting)
+
+	rules := theme.SemanticTokenColors
+	require.Len(t, rules, 3, "invalid selectors and empty styles are dropped")
+
+	assert.Equal(t, "*.deprecated", rules[0].Selector)
+	assert.Equal(t, "*", rules[0].TokenType)
+	assert.Equal(t, []string{"deprecated"}, rules[0].Modifiers)
+	require.NotNil(t, rules[0].Style.Strikethrough)
+	assert.True(t, *rules[0].Style.Strikethrough)
+
+	assert.Equal(t, "function", rules[1].TokenType)
+	assert.Equal(t, []string{"declaration", "async"}, rules[1].Modifiers)
+	assert.Equal(t, "typescript", rules[1].Language)
+	assert.Equal(t, "#DCDCAA", rules[1].Style.Foreground)
+	assert.Equal(t, "italic bold", rules[1].Style.FontStyle)
+	require.NotNil(t, rules[1].Style.Underline)
+	assert.False(t, *rules[1].Style.Underline, "explicit false is kept")
+
+	assert.Equal(t, "variable", rules[2].TokenType)
+	assert.Equal(t, []string{"readonly"}, rules[2].Modifiers)
+	assert.Equal(t, "#4FC1FF", rules[2].Style.Foreground)
+}
+
+func TestParseSemanticSelector(t *testing.T) {
+	tokenType, modifiers, language, ok := importer.ParseSemanticSelector("class:java")
+	assert.True(t, ok)
+	assert.Equal(t, "class", tokenType)
+	assert.Empty(t, modifiers)
+	assert.Equal(t, "java", language)
+
+	for _, bad := range []string{"", ".readonly", "variable.", "a:b:c", "var iable"} {
+		_, _, _, ok := importer.ParseSemanticSelector(bad)
+		assert.False(t, ok, bad)
+	}
+}
+
+func TestImportOrchestraFillsSemanticSelectors(t *testing.T) {
+	theme, err := importer.Import([]byte(`{"id": "x", "semantic_token_colors": [{"selector": "property.static:go", "style": {"bold": true}}]}`))
+	require.NoError(t, err)
+	require.Len(t, theme.SemanticTokenColors, 1)
+	assert.Equal(t, "property", theme.SemanticTokenColors[0].TokenType)
+	assert.Equal(t, []string{"static"}, theme.SemanticTokenColors[0].Modifiers)
+	assert.Equal(t, "go", theme.SemanticTokenColors[0].Language)
+}
+
+// --- Include Bundles ---
+
+var sampleBundle = importer.Bundle{
+	"themes/dark.json": []byte(`{
+		"name": "Child Dark",
+		"include": "./base/common.json",
+		"colors": { "editor.background": "#101010" },
+		"tokenColors": [
+			{ "scope": "comment", "settings": { "foreground": "#888888" } }
+		]
+	}`),
+	"themes/base/common.json": []byte(`{
+		"name": "Common",
+		"include": "../../root.jsonc",
+		"colors": {
+			"editor.background": "#202020",
+			"editor.foreground": "#E0E0E0"
+		},
+		"tokenColors": [
+			{ "scope": "comment", "settings": { "foreground": "#666666" } }
+		],
+		"semanticTokenColors": { "variable": "#AAAAAA" }
+	}`),
+	"root.jsonc": []byte(`{
+		// Shared base
+		"type": "dark",
+		"semanticHighlighting": true,
+		"colors": { "statusBar.background": "#007ACC" },
+		"tokenColors": [
+			{ "scope": "string", "settings": { "foreground": "#CE9178" } }
+		]
+	}`),
+	"README.md": []byte("# Theme"),
+}
+
+func TestImportVSCodeBundleResolvesChain(t *testing.T) {
+	theme, err := importer.ImportVSCodeBundle(sampleBundle, "themes/dark.json")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Child Dark", theme.Name)
+	assert.Equal(t, "dark", theme.Type, "type is inherited")
+	assert.Equal(t, "#101010", theme.Colors["editor.background"], "child overrides parent")
+	assert.Equal(t, "#E0E0E0", theme.Colors["editor.foreground"])
+	assert.Equal(t, "#007ACC", theme.Colors["statusbar.background"])
+	assert.NotContains(t, theme.Colors, "_include")
+
+	require.Len(t, theme.TokenColors, 3)
+	assert.Equal(t, "#CE9178", theme.TokenColors[0].Settings["foreground"], "parent rules come first")
+	assert.Equal(t, "#666666", theme.TokenColors[1].Settings["foreground"])
+	assert.Equal(t, "#888888", theme.TokenColors[2].Settings["foreground"])
+
+	require.NotNil(t, theme.SemanticHighlighting)
+	assert.True(t, *theme.SemanticHighlighting)
+	require.Len(t, theme.SemanticTokenColors, 1)
+	assert.Equal(t, "variable", theme.SemanticTokenColors[0].Selector)
+}
+
+func TestImportVSCodeBundleDetectsEntry(t *testing.T) {
+	theme, err := importer.ImportVSCodeBundle(sampleBundle, "")
+	require.NoError(t, err)
+	assert.Equal(t, "Child Dark", theme.Name)
+}
+
+func TestImportVSCodeBundleAmbiguousEntry(t *testing.T) {
+	bundle := importer.Bundle{
+		"a.json": []byte(`{"name": "A"}`),
+		"b.json": []byte(`{"name": "B"}`),
+	}
+	_, err := importer.ImportVSCodeBundle(bundle, "")
+	require.ErrorIs(t, err, importer.ErrBundleEntry)
+	assert.Contains(t, err.Error(), "a.json, b.json")
+}
+
+func TestImportVSCodeBundleMissingInclude(t *testing.T) {
+	bundle := importer.Bundle{
+		"dark.json": []byte(`{"name": "Dark", "include": "./missing.json"}`),
+	}
+	_, err := importer.ImportVSCodeBundle(bundle, "dark.json")
+	require.ErrorIs(t, err, importer.ErrIncludeMissing)
+	assert.Contains(t, err.Error(), `dark.json includes "./missing.json"`)
+}
+
+func TestImportVSCodeBundleCycle(t *testing.T) {
+	bundle := importer.Bundle{
+		"a.json": []byte(`{"name": "A", "include": "./b.json"}`),
+		"b.json": []byte(`{"name": "B", "include": "./a.json"}`),
+	}
+	_, err := importer.ImportVSCodeBundle(bundle, "a.json")
+	require.ErrorIs(t, err, importer.ErrIncludeCycle)
+	assert.Contains(t, err.Error(), "a.json -> b.json -> a.json")
+}
+
+func TestReadZipBundle(t *testing.T) {
+	files := make(map[string][]byte, len(sampleBundle))
+	for name, data := range sampleBundle {
+		files["extension/"+name] = data
+	}
+	bundle, err := importer.ReadZipBundle(makeZip(t, files))
+	require.NoError(t, err)
+	assert.Len(t, bundle, len(sampleBundle))
+
+	theme, err := importer.ImportVSCodeBundle(bundle, "extension/themes/dark.json")
+	require.NoError(t, err)
+	assert.Equal(t, "#E0E0E0", theme.Colors["editor.foreground"])
+}
+
+func TestReadZipBundleInvalid(t *testing.T) {
+	_, err := importer.ReadZipBundle([]byte("not a zip"))
+	assert.Error(t, err)
+}
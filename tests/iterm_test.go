@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// itermColor formats an .itermcolors component dict. An empty space
+// omits the "Color Space" key.
+func itermColor(r, g, b float64, space string) string {
+	s := fmt.Sprintf(`<dict>
+		<key>Blue Component</key><real>%g</real>
+		<key>Green Component</key><real>%g</real>
+		<key>Red Component</key><real>%g</real>`, b, g, r)
+	if space != "" {
+		s += "\n\t\t<key>Color Space</key><string>" + space + "</string>"
+	}
+	return s + "\n\t</dict>"
+}
+
+// itermColors builds an .itermcolors plist with 16 gray ANSI colors and
+// the given extra entries.
+func itermColors(extra map[string]string) []byte {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	for i := 0; i < 16; i++ {
+		v := float64(i) / 15
+		fmt.Fprintf(&b, "\t<key>Ansi %d Color</key>\n\t%s\n", i, itermColor(v, v, v, "sRGB"))
+	}
+	for key, dict := range extra {
+		fmt.Fprintf(&b, "\t<key>%s</key>\n\t%s\n", key, dict)
+	}
+	b.WriteString("</dict>\n</plist>\n")
+	return []byte(b.String())
+}
+
+var sampleITermColors = itermColors(map[string]string{
+	"Background Color":    itermColor(0.156863, 0.172549, 0.203922, "sRGB"),
+	"Foreground Color":    itermColor(0.670588, 0.698039, 0.749020, "sRGB"),
+	"Cursor Color":        itermColor(0.8, 0.4, 0.2, "P3"),
+	"Selection Color":     itermColor(0.5, 0.25, 1.0, "Calibrated"),
+	"Selected Text Color": itermColor(0.5, 0.25, 1.0, ""),
+	"Link Color": `<dict>
+		<key>Alpha Component</key><real>0.5</real>
+		<key>Blue Component</key><real>1</real>
+		<key>Color Space</key><string>sRGB</string>
+		<key>Green Component</key><real>0</real>
+		<key>Red Component</key><real>0</real>
+	</dict>`,
+})
+
+func TestDetectFormatITermColors(t *testing.T) {
+	assert.Equal(t, importer.FormatITermColors, importer.DetectFormat(sampleITermColors))
+	assert.Equal(t, importer.FormatTmTheme, importer.DetectFormat(sampleTmTheme))
+}
+
+func TestImportITermColors(t *testing.T) {
+	theme, err := importer.Import(sampleITermColors)
+	require.NoError(t, err)
+
+	assert.Equal(t, "itermcolors", theme.Source)
+	assert.Equal(t, "dark", theme.Type)
+	assert.Equal(t, "#282C34", theme.Colors["background"])
+	assert.Equal(t, "#282C34", theme.Colors["editor.background"])
+	assert.Equal(t, "#ABB2BF", theme.Colors["editor.foreground"])
+	assert.Equal(t, "#DB5E1F", theme.Colors["editor.cursor"], "Display P3 is converted to sRGB")
+	assert.Equal(t, "#9251FF", theme.Colors["editor.selection"], "calibrated RGB uses gamma 1.8")
+	assert.Equal(t, "#9251FF", theme.Colors["raw.Selected Text Color"], "no color space means calibrated")
+	assert.Equal(t, "#0000FF80", theme.Colors["raw.Link Color"])
+
+	assert.Equal(t, "#000000", theme.Colors["raw.Ansi 0 Color"])
+	assert.Equal(t, "#FFFFFF", theme.Colors["raw.Ansi 15 Color"])
+}
+
+func TestImportITermColorsBadComponents(t *testing.T) {
+	_, err := importer.ImportITermColors(itermColors(map[string]string{
+		"Cursor Color": `<dict><key>Red Component</key><real>1</real></dict>`,
+	}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Cursor Color: missing green component")
+
+	_, err = importer.ImportITermColors(itermColors(map[string]string{
+		"Cursor Color": itermColor(1, 1, 1, "CMYK"),
+	}))
+	assert.ErrorContains(t, err, "unsupported color space")
+
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err = importer.ImportITermColors(itermColors(map[string]string{
+			"Link Color": itermColor(v, 0, 0, "sRGB"),
+		}))
+		assert.ErrorContains(t, err, "Link Color: red component is not a finite number", "%g", v)
+	}
+}
+
+func TestImportFileNamesNamelessFormats(t *testing.T) {
+	theme, err := importer.ImportFile("themes/Tomorrow Night.itermcolors", sampleITermColors)
+	require.NoError(t, err)
+	assert.Equal(t, "Tomorrow Night", theme.Name)
+	assert.Equal(t, "tomorrow-night", theme.ID)
+
+	tm, err := importer.ImportFile("other-name.tmTheme", sampleTmTheme)
+	require.NoError(t, err)
+	assert.Equal(t, "Monokai", tm.Name, "embedded names are kept")
+}
@@ -2,8 +2,11 @@ package tests
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/orchestra-mcp/themes/src/builtin"
@@ -126,7 +129,7 @@ func TestChangeListener(t *testing.T) {
 func TestExportTheme(t *testing.T) {
 	svc := newTestService(t)
 
-	data, err := svc.ExportTheme("orchestra-dark")
+	data, err := svc.ExportTheme("orchestra-dark", service.ExportOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -142,7 +145,7 @@ func TestExportTheme(t *testing.T) {
 
 func TestExportThemeNotFound(t *testing.T) {
 	svc := newTestService(t)
-	_, err := svc.ExportTheme("nonexistent")
+	_, err := svc.ExportTheme("nonexistent", service.ExportOptions{})
 	if err == nil {
 		t.Fatal("expected error for nonexistent theme export")
 	}
@@ -239,3 +242,331 @@ func TestBuiltinThemeColorKeys(t *testing.T) {
 		}
 	}
 }
+
+func TestUserThemePersistence(t *testing.T) {
+	dir := t.TempDir()
+	logger := zerolog.Nop()
+
+	svc1 := service.New(dir, "orchestra-dark", logger)
+	custom := &types.ThemeDef{
+		ID:     "persisted-theme",
+		Name:   "Persisted Theme",
+		Type:   "dark",
+		Colors: map[string]string{"background": "#101010"},
+	}
+	if err := svc1.RegisterTheme(custom); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc1.SetActiveTheme("persisted-theme"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A new instance should reload the theme before applying the preference.
+	svc2 := service.New(dir, "orchestra-dark", logger)
+	got, err := svc2.GetTheme("persisted-theme")
+	if err != nil {
+		t.Fatalf("expected persisted theme: %v", err)
+	}
+	if got.Colors["background"] != "#101010" {
+		t.Errorf("expected background '#101010', got %q", got.Colors["background"])
+	}
+	if active := svc2.GetActiveTheme(); active.ID != "persisted-theme" {
+		t.Errorf("expected active 'persisted-theme', got %q", active.ID)
+	}
+}
+
+func TestImportedThemePersistence(t *testing.T) {
+	dir := t.TempDir()
+	logger := zerolog.Nop()
+
+	svc1 := service.New(dir, "orchestra-dark", logger)
+	if _, err := svc1.ImportTheme([]byte(`{"id":"imported","name":"Imported","type":"light"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svc2 := service.New(dir, "orchestra-dark", logger)
+	if _, err := svc2.GetTheme("imported"); err != nil {
+		t.Fatalf("expected imported theme after restart: %v", err)
+	}
+}
+
+func TestRegisterThemeBuiltinReadOnly(t *testing.T) {
+	svc := newTestService(t)
+	err := svc.RegisterTheme(&types.ThemeDef{ID: "orchestra-dark", Name: "Hijacked"})
+	if !errors.Is(err, service.ErrBuiltinTheme) {
+		t.Fatalf("expected ErrBuiltinTheme, got %v", err)
+	}
+
+	dark, _ := svc.GetTheme("orchestra-dark")
+	if dark.Name != "Orchestra Dark" {
+		t.Errorf("built-in theme was modified: %q", dark.Name)
+	}
+	if !svc.IsBuiltin("orchestra-dark") || svc.IsBuiltin("custom") {
+		t.Error("IsBuiltin reported wrong result")
+	}
+}
+
+func TestStoredThemeCannotShadowBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	stored := `{"themes":[{"id":"orchestra-light","name":"Fake"},{"id":"extra","name":"Extra"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "user-themes.json"), []byte(stored), 0o644); err != nil {
+		t.Fatalf("write store: %v", err)
+	}
+
+	svc := service.New(dir, "orchestra-dark", zerolog.Nop())
+	light, _ := svc.GetTheme("orchestra-light")
+	if light.Name != "Orchestra Light" {
+		t.Errorf("stored theme shadowed built-in: %q", light.Name)
+	}
+	if _, err := svc.GetTheme("extra"); err != nil {
+		t.Errorf("expected stored theme 'extra': %v", err)
+	}
+}
+
+func registerCustom(t *testing.T, svc *service.ThemesService, id string) {
+	t.Helper()
+	err := svc.RegisterTheme(&types.ThemeDef{
+		ID:   id,
+		Name: "Custom",
+		Type: "dark",
+		Colors: map[string]string{
+			"background": "#000000",
+			"foreground": "#FFFFFF",
+		},
+	})
+	if err != nil {
+		t.Fatalf("register %s: %v", id, err)
+	}
+}
+
+func TestDeleteTheme(t *testing.T) {
+	dir := t.TempDir()
+	svc := service.New(dir, "orchestra-dark", zerolog.Nop())
+	registerCustom(t, svc, "doomed")
+
+	if err := svc.DeleteTheme("doomed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.GetTheme("doomed"); !errors.Is(err, service.ErrThemeNotFound) {
+		t.Errorf("expected ErrThemeNotFound, got %v", err)
+	}
+
+	reloaded := service.New(dir, "orchestra-dark", zerolog.Nop())
+	if _, err := reloaded.GetTheme("doomed"); err == nil {
+		t.Error("deleted theme came back after restart")
+	}
+}
+
+func TestDeleteThemeErrors(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.DeleteTheme("orchestra-light"); !errors.Is(err, service.ErrBuiltinTheme) {
+		t.Errorf("expected ErrBuiltinTheme, got %v", err)
+	}
+	if err := svc.DeleteTheme("missing"); !errors.Is(err, service.ErrThemeNotFound) {
+		t.Errorf("expected ErrThemeNotFound, got %v", err)
+	}
+}
+
+func TestDeleteActiveThemeFallsBackToDefault(t *testing.T) {
+	svc := service.New(t.TempDir(), "orchestra-light", zerolog.Nop())
+	registerCustom(t, svc, "active-custom")
+	if err := svc.SetActiveTheme("active-custom"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var oldID, newID string
+	svc.OnDidChangeTheme(func(old, new *types.ThemeDef) {
+		oldID, newID = old.ID, new.ID
+	})
+
+	if err := svc.DeleteTheme("active-custom"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if active := svc.GetActiveTheme(); active.ID != "orchestra-light" {
+		t.Errorf("expected fallback 'orchestra-light', got %q", active.ID)
+	}
+	if oldID != "active-custom" || newID != "orchestra-light" {
+		t.Errorf("unexpected listener call: %q -> %q", oldID, newID)
+	}
+}
+
+func TestUpdateTheme(t *testing.T) {
+	svc := newTestService(t)
+	registerCustom(t, svc, "editable")
+
+	updated, err := svc.UpdateTheme("editable", &types.ThemeDef{
+		Name:   "Edited",
+		Type:   "light",
+		Colors: map[string]string{"background": "#FFFFFF"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.ID != "editable" {
+		t.Errorf("expected ID to be filled in, got %q", updated.ID)
+	}
+
+	got, _ := svc.GetRawTheme("editable")
+	if got.Name != "Edited" || len(got.Colors) != 1 {
+		t.Errorf("expected full replacement, got %+v", got)
+	}
+}
+
+func TestUpdateThemeErrors(t *testing.T) {
+	svc := newTestService(t)
+	registerCustom(t, svc, "editable")
+
+	if _, err := svc.UpdateTheme("orchestra-dark", &types.ThemeDef{}); !errors.Is(err, service.ErrBuiltinTheme) {
+		t.Errorf("expected ErrBuiltinTheme, got %v", err)
+	}
+	if _, err := svc.UpdateTheme("missing", &types.ThemeDef{}); !errors.Is(err, service.ErrThemeNotFound) {
+		t.Errorf("expected ErrThemeNotFound, got %v", err)
+	}
+	if _, err := svc.UpdateTheme("editable", &types.ThemeDef{ID: "renamed"}); err == nil {
+		t.Error("expected error when changing theme ID")
+	}
+}
+
+func TestUpdateActiveThemeFiresListeners(t *testing.T) {
+	svc := newTestService(t)
+	registerCustom(t, svc, "live")
+	if err := svc.SetActiveTheme("live"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fired := 0
+	svc.OnDidChangeTheme(func(_, _ *types.ThemeDef) { fired++ })
+	if _, err := svc.UpdateTheme("live", &types.ThemeDef{Name: "Live v2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fired != 1 {
+		t.Errorf("expected 1 listener call, got %d", fired)
+	}
+}
+
+func TestReregisterActiveThemeFiresListeners(t *testing.T) {
+	svc := newTestService(t)
+	registerCustom(t, svc, "live")
+	if err := svc.SetActiveTheme("live"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got *types.ThemeDef
+	svc.OnDidChangeTheme(func(_, new *types.ThemeDef) { got = new })
+	err := svc.RegisterTheme(&types.ThemeDef{
+		ID:     "live",
+		Name:   "Live v2",
+		Type:   "dark",
+		Colors: map[string]string{"background": "#101010", "foreground": "#FFFFFF"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || got.Colors["background"] != "#101010" {
+		t.Errorf("expected listener with the new definition, got %+v", got)
+	}
+
+	got = nil
+	registerCustom(t, svc, "unrelated")
+	if got != nil {
+		t.Errorf("registering an inactive theme fired listeners")
+	}
+}
+
+func TestDeleteBaseOfActiveThemeFiresListeners(t *testing.T) {
+	svc := newTestService(t)
+	registerCustom(t, svc, "base")
+	err := svc.RegisterTheme(&types.ThemeDef{
+		ID:      "child",
+		Name:    "Child",
+		Type:    "dark",
+		Extends: "base",
+		Colors:  map[string]string{"foreground": "#EEEEEE"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.SetActiveTheme("child"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fired := 0
+	svc.OnDidChangeTheme(func(_, _ *types.ThemeDef) { fired++ })
+	if err := svc.DeleteTheme("base"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fired != 1 {
+		t.Errorf("expected 1 listener call, got %d", fired)
+	}
+}
+
+func TestPatchTheme(t *testing.T) {
+	svc := newTestService(t)
+	registerCustom(t, svc, "patchable")
+
+	patch := []byte(`{
+		"colors": {"foreground": null, "accent": "#FF00FF"},
+		"token_colors": [{"name": "Comment", "scope": ["comment"], "settings": {"foreground": "#888888"}}]
+	}`)
+	patched, err := svc.PatchTheme("patchable", patch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := patched.Colors["foreground"]; ok {
+		t.Error("expected null to remove 'foreground'")
+	}
+	if patched.Colors["background"] != "#000000" {
+		t.Errorf("expected untouched background, got %q", patched.Colors["background"])
+	}
+	if patched.Colors["accent"] != "#FF00FF" {
+		t.Errorf("expected accent '#FF00FF', got %q", patched.Colors["accent"])
+	}
+	if len(patched.TokenColors) != 1 || patched.TokenColors[0].Name != "Comment" {
+		t.Errorf("expected token colors to be replaced, got %+v", patched.TokenColors)
+	}
+}
+
+func TestConcurrentPatchesKeepEveryUpdate(t *testing.T) {
+	svc := newTestService(t)
+	registerCustom(t, svc, "patchable")
+
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			patch := fmt.Sprintf(`{"colors": {"raw.patch-%d": "#000000"}}`, i)
+			if _, err := svc.PatchTheme("patchable", []byte(patch)); err != nil {
+				t.Errorf("patch %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	theme, err := svc.GetRawTheme("patchable")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if _, ok := theme.Colors[fmt.Sprintf("raw.patch-%d", i)]; !ok {
+			t.Errorf("patch %d was lost", i)
+		}
+	}
+}
+
+func TestPatchThemeErrors(t *testing.T) {
+	svc := newTestService(t)
+	registerCustom(t, svc, "patchable")
+
+	if _, err := svc.PatchTheme("orchestra-dark", []byte(`{"name":"x"}`)); !errors.Is(err, service.ErrBuiltinTheme) {
+		t.Errorf("expected ErrBuiltinTheme, got %v", err)
+	}
+	if _, err := svc.PatchTheme("patchable", []byte(`not json`)); err == nil {
+		t.Error("expected error for invalid patch JSON")
+	}
+	if _, err := svc.PatchTheme("patchable", []byte(`{"id":"other"}`)); err == nil {
+		t.Error("expected error when patching theme ID")
+	}
+}
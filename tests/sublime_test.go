@@ -0,0 +1,233 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/themes/src/color"
+	"github.com/orchestra-mcp/themes/src/colorkeys"
+	"github.com/orchestra-mcp/themes/src/contrast"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Sublime Text .sublime-color-scheme Import ---
+
+var sampleSublimeScheme = []byte(`{
+	// Sublime accepts comments and trailing commas.
+	"name": "Harbor",
+	"author": "Jane Doe",
+	"variables": {
+		"blue": "#6699CC",
+		"accent": "var(blue)",
+		"bg": "#303841",
+		"white": "#D8DEE9",
+	},
+	"globals": {
+		"background": "var(bg)",
+		"foreground": "var(white)",
+		"caret": "var(accent)",
+		"selection": "color(var(blue) alpha(0.5))",
+		"line_highlight": "color(var(bg) blend(#FFFFFF 90%))",
+		"popup_css": "html { color: red; }",
+	},
+	"rules": [
+		{
+			"name": "Comment",
+			"scope": "comment, punctuation.definition.comment",
+			"foreground": "color(#FF0000 l(25%))",
+			"font_style": "italic glow squiggly_underline",
+		},
+		{
+			"scope": "variable",
+			"foreground": ["#ff0000", "#00ff00"],
+			"background": "var(bg)",
+		},
+	],
+}`)
+
+func TestDetectFormatSublime(t *testing.T) {
+	assert.Equal(t, importer.FormatSublime, importer.DetectFormat(sampleSublimeScheme))
+	assert.Equal(t, importer.FormatSublime, importer.DetectFormat([]byte(`{"rules": []}`)))
+	assert.Equal(t, importer.FormatSublime, importer.DetectFormat([]byte(`{"globals": {"background": "#000"`)),
+		"malformed schemes are routed to the importer to report the error")
+}
+
+func TestImportSublime(t *testing.T) {
+	theme, err := importer.Import(sampleSublimeScheme)
+	require.NoError(t, err)
+
+	assert.Equal(t, "harbor", theme.ID)
+	assert.Equal(t, "Harbor", theme.Name)
+	assert.Equal(t, "Jane Doe", theme.Author)
+	assert.Equal(t, "sublime", theme.Source)
+	assert.Equal(t, "dark", theme.Type)
+
+	assert.Equal(t, "#303841", theme.Colors[colorkeys.Background])
+	assert.Equal(t, "#303841", theme.Colors[colorkeys.EditorBackground])
+	assert.Equal(t, "#D8DEE9", theme.Colors[colorkeys.EditorForeground])
+	assert.Equal(t, "#6699CC", theme.Colors[colorkeys.EditorCursor], "variables may reference variables")
+	assert.Equal(t, "#6699CC80", theme.Colors[colorkeys.EditorSelection])
+	assert.Equal(t, "#454C54", theme.Colors[colorkeys.EditorLineHighlight])
+	assert.Equal(t, "#454C54", theme.Colors[colorkeys.RawPrefix+"line_highlight"])
+	assert.Equal(t, "html { color: red; }", theme.Colors[colorkeys.RawPrefix+"popup_css"])
+
+	require.Len(t, theme.TokenColors, 2)
+	comment := theme.TokenColors[0]
+	assert.Equal(t, "Comment", comment.Name)
+	assert.Equal(t, []string{"comment", "punctuation.definition.comment"}, comment.Scope)
+	assert.Equal(t, "#800000", comment.Settings["foreground"])
+	assert.Equal(t, "italic underline", comment.Settings["fontStyle"])
+
+	variable := theme.TokenColors[1]
+	assert.Equal(t, "#FF0000", variable.Settings["foreground"], "hashed highlighting uses the first color")
+	assert.Equal(t, "#303841", variable.Settings["background"])
+}
+
+func sublimeRuleScheme(foreground string) []byte {
+	return []byte(fmt.Sprintf(`{"rules": [{"scope": "source", "foreground": %q}]}`, foreground))
+}
+
+func TestImportSublimeColorAdjusters(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"color(#6699CC alpha(0.5))", "#6699CC80"},
+		{"color(#6699CC a(25%))", "#6699CC40"},
+		{"color(#6699CC80 alpha(* 0.5))", "#6699CC40"},
+		{"color(#FF0000 l(25%))", "#800000"},
+		{"color(#FF0000 lightness(- 25%))", "#800000"},
+		{"color(#FF0000 s(0%))", "#808080"},
+		{"color(#000000 blend(#FFFFFF 25%))", "#BFBFBF"},
+		{"color(#00000000 blend(#FFFFFF 50%))", "#80808000"},
+		{"color(#00000000 blenda(#FFFFFF 50%))", "#80808080"},
+		{"color(#FF0000 blend(#0000FF 50% hsl))", "#FF00FF"},
+		{"color(color(#FF0000 l(25%)) alpha(0.5))", "#80000080"},
+		{"rgb(102, 153, 204)", "#6699CC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			theme, err := importer.ImportSublime(sublimeRuleScheme(tt.expr))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, theme.TokenColors[0].Settings["foreground"])
+		})
+	}
+
+	theme, err := importer.ImportSublime(sublimeRuleScheme("color(#777777 min-contrast(#888888 4.5))"))
+	require.NoError(t, err)
+	fg := color.MustParse(theme.TokenColors[0].Settings["foreground"])
+	assert.GreaterOrEqual(t, contrast.Ratio(fg, color.MustParse("#888888")), 4.5)
+}
+
+func TestImportSublimeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unknown variable", `{"rules": [{"scope": "a", "foreground": "var(nope)"}]}`, `rules[0]: foreground: unknown variable "nope"`},
+		{"cycle", `{"variables": {"a": "var(b)", "b": "var(a)"}, "globals": {"background": "var(a)"}}`, "globals.background: variable cycle: a -> b -> a"},
+		{"adjuster", `{"rules": [{"scope": "a", "background": "color(#fff hue(10))"}]}`, `unsupported color adjuster "hue"`},
+		{"nan amount", `{"rules": [{"scope": "a", "foreground": "color(#000000 l(NaN))"}]}`, `invalid amount "NaN"`},
+		{"inf percentage", `{"rules": [{"scope": "a", "foreground": "color(#000000 blend(blue Inf%))"}]}`, `invalid percentage "Inf%"`},
+		{"inf ratio", `{"rules": [{"scope": "a", "foreground": "color(#777777 min-contrast(#888888 infinity))"}]}`, `invalid ratio "infinity"`},
+		{"empty", `{"rules": []}`, "no globals or rules"},
+		{"syntax", `{"rules": [}`, "invalid Sublime color scheme JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := importer.ImportSublime([]byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestImportSublimeLimitsVariableExpansion(t *testing.T) {
+	vars := []string{`"v0": "#FF0000"`}
+	for i := 1; i <= 40; i++ {
+		vars = append(vars, fmt.Sprintf(`"v%d": "var(v%d) var(v%d)"`, i, i-1, i-1))
+	}
+	data := fmt.Sprintf(`{"variables": {%s}, "globals": {"background": "var(v40)"}}`, strings.Join(vars, ", "))
+
+	start := time.Now()
+	_, err := importer.ImportSublime([]byte(data))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expands beyond")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestImportSublimeFileNamesUnnamedSchemes(t *testing.T) {
+	data := []byte(`{"globals": {"background": "#FFFFFF", "foreground": "#333333"}}`)
+	theme, err := importer.ImportFile("Paper Light.sublime-color-scheme", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Paper Light", theme.Name)
+	assert.Equal(t, "paper-light", theme.ID)
+	assert.Equal(t, "light", theme.Type)
+}
+
+var sampleSublimePackage = map[string][]byte{
+	"Harbor.sublime-color-scheme":         sampleSublimeScheme,
+	"schemes/Paper.sublime-color-scheme":  []byte(`{"globals": {"background": "#FFFFFF"}}`),
+	"schemes/Broken.sublime-color-scheme": []byte(`{"globals": {"background": "var(missing)"}}`),
+	"Monokai.tmTheme":                     sampleTmTheme,
+	"Default.sublime-keymap":              []byte(`[]`),
+	"messages/install.txt":                []byte("Thanks!"),
+}
+
+func TestImportSublimePackage(t *testing.T) {
+	themes, err := importer.ImportSublimePackage(makeZip(t, sampleSublimePackage))
+	require.NoError(t, err)
+	require.Len(t, themes, 4)
+
+	byPath := make(map[string]importer.ExtensionTheme)
+	for _, et := range themes {
+		byPath[et.Path] = et
+	}
+
+	harbor := byPath["Harbor.sublime-color-scheme"]
+	require.NoError(t, harbor.Err)
+	assert.Equal(t, "Harbor", harbor.Label)
+	assert.Equal(t, "harbor", harbor.Theme.ID)
+
+	paper := byPath["schemes/Paper.sublime-color-scheme"]
+	require.NoError(t, paper.Err)
+	assert.Equal(t, "Paper", paper.Theme.Name, "unnamed schemes are named after their file")
+	assert.Equal(t, "paper", paper.Theme.ID)
+
+	monokai := byPath["Monokai.tmTheme"]
+	require.NoError(t, monokai.Err)
+	assert.Equal(t, "tmtheme", monokai.Theme.Source)
+
+	broken := byPath["schemes/Broken.sublime-color-scheme"]
+	assert.Nil(t, broken.Theme)
+	require.Error(t, broken.Err)
+	assert.Contains(t, broken.Err.Error(), "missing")
+}
+
+func TestImportSublimePackageWithoutSchemes(t *testing.T) {
+	_, err := importer.ImportSublimePackage(makeZip(t, map[string][]byte{"Default.sublime-keymap": []byte(`[]`)}))
+	assert.ErrorIs(t, err, importer.ErrInvalidPackage)
+
+	_, err = importer.ImportSublimePackage([]byte("not a zip"))
+	assert.Error(t, err)
+}
+
+func TestServiceImportSublimePackage(t *testing.T) {
+	svc := newTestService(t)
+	result, err := svc.ImportSublimePackage("Harbor Schemes", makeZip(t, sampleSublimePackage), service.DiscoverOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Harbor Schemes", result.Extension)
+	assert.Equal(t, 3, result.Imported)
+	require.Len(t, result.Themes, 4)
+
+	theme, err := svc.GetTheme("harbor")
+	require.NoError(t, err)
+	assert.Equal(t, "#6699CC80", theme.Colors[colorkeys.EditorSelection])
+}
@@ -0,0 +1,212 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/orchestra-mcp/themes/src/builtin"
+	"github.com/orchestra-mcp/themes/src/exporter"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/types"
+	"github.com/orchestra-mcp/themes/src/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinTerminalPalettesAreComplete(t *testing.T) {
+	for _, theme := range builtin.BuiltinThemes() {
+		require.NotNil(t, theme.Terminal, theme.ID)
+		for _, name := range types.TerminalColorNames {
+			assert.NotEmpty(t, *theme.Terminal.Field(name), "%s terminal %s", theme.ID, name)
+		}
+		assert.Equal(t, theme.Colors["editor.background"], theme.Terminal.Background, theme.ID)
+		assert.Empty(t, validator.Validate(theme).Errors(), theme.ID)
+	}
+
+	svc := newTestService(t)
+	got, err := svc.GetTheme("orchestra-dark")
+	require.NoError(t, err)
+	assert.Equal(t, builtin.DarkTheme().Terminal, got.Terminal)
+	assert.Empty(t, got.Synthesized)
+}
+
+func TestTerminalPaletteFields(t *testing.T) {
+	p := &types.TerminalPalette{Red: "#FF0000", BrightWhite: "#FFFFFF"}
+	ansi := p.ANSI()
+	assert.Equal(t, "#FF0000", ansi[1])
+	assert.Equal(t, "#FFFFFF", ansi[15])
+	assert.Nil(t, p.Field("orange"))
+
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"red":"#FF0000","bright_white":"#FFFFFF"}`, string(data))
+}
+
+func TestImportVSCodeTerminalColors(t *testing.T) {
+	theme, err := importer.ImportVSCode([]byte(`{
+		"name": "Term",
+		"type": "dark",
+		"colors": {
+			"editor.background": "#101010",
+			"terminal.ansiRed": "#ff5555",
+			"terminal.ansiBrightBlue": "#8899FF",
+			"terminalCursor.foreground": "#FFFFFF",
+			"terminal.selectionBackground": "#44475a80"
+		}
+	}`))
+	require.NoError(t, err)
+	require.NotNil(t, theme.Terminal)
+	assert.Equal(t, "#FF5555", theme.Terminal.Red)
+	assert.Equal(t, "#8899FF", theme.Terminal.BrightBlue)
+	assert.Equal(t, "#FFFFFF", theme.Terminal.Cursor)
+	assert.Equal(t, "#44475A80", theme.Terminal.Selection)
+	assert.Empty(t, theme.Terminal.Green)
+	assert.Equal(t, "#ff5555", theme.Colors["raw.terminal.ansiRed"], "raw keys are kept")
+
+	plain, err := importer.ImportVSCode([]byte(`{"name": "Plain", "colors": {"editor.background": "#101010"}}`))
+	require.NoError(t, err)
+	assert.Nil(t, plain.Terminal)
+}
+
+func TestImportITermColorsTerminal(t *testing.T) {
+	theme, err := importer.ImportITermColors(sampleITermColors)
+	require.NoError(t, err)
+	require.NotNil(t, theme.Terminal)
+	assert.Equal(t, "#000000", theme.Terminal.Black)
+	assert.Equal(t, "#111111", theme.Terminal.Red)
+	assert.Equal(t, "#FFFFFF", theme.Terminal.BrightWhite)
+	assert.Equal(t, "#282C34", theme.Terminal.Background)
+	assert.Equal(t, "#DB5E1F", theme.Terminal.Cursor)
+	assert.Equal(t, "#9251FF", theme.Terminal.Selection)
+}
+
+func TestCompleteDerivesTerminalPalette(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:   "no-terminal",
+		Type: "dark",
+		Colors: map[string]string{
+			"background":    "#000000",
+			"foreground":    "#FFFFFF",
+			"editor.cursor": "#FFCC00",
+			"error":         "#FF3333",
+			"success":       "#33FF33",
+		},
+	}))
+
+	got, err := svc.GetTheme("no-terminal")
+	require.NoError(t, err)
+	require.NotNil(t, got.Terminal)
+	for _, name := range types.TerminalColorNames {
+		assert.NotEmpty(t, *got.Terminal.Field(name), name)
+		assert.Contains(t, got.Synthesized, "terminal."+name)
+	}
+	assert.Equal(t, "#000000", got.Terminal.Background)
+	assert.Equal(t, "#FFFFFF", got.Terminal.Foreground)
+	assert.Equal(t, "#FFCC00", got.Terminal.Cursor)
+	assert.Equal(t, "#FF3333", got.Terminal.Red, "red follows the error color")
+	assert.Equal(t, "#33FF33", got.Terminal.Green, "green follows the success color")
+	assert.Equal(t, "#262626", got.Terminal.Black, "black sits just above the background")
+	assert.Equal(t, "#FFFFFF", got.Terminal.BrightWhite)
+	assert.Equal(t, builtin.DarkTheme().Terminal.Cyan, got.Terminal.Cyan)
+	assert.NotEqual(t, got.Terminal.Red, got.Terminal.BrightRed)
+
+	raw, err := svc.GetRawTheme("no-terminal")
+	require.NoError(t, err)
+	assert.Nil(t, raw.Terminal, "stored theme is not modified")
+}
+
+func TestCompleteKeepsPartialTerminalPalette(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:       "partial-terminal",
+		Type:     "light",
+		Colors:   map[string]string{"background": "#FFFFFF", "foreground": "#000000"},
+		Terminal: &types.TerminalPalette{Red: "rgb(200, 0, 0)", Black: "#111"},
+	}))
+
+	raw, err := svc.GetRawTheme("partial-terminal")
+	require.NoError(t, err)
+	assert.Equal(t, "#C80000", raw.Terminal.Red, "terminal colors are normalized")
+	assert.Equal(t, "#111111", raw.Terminal.Black)
+
+	got, err := svc.GetTheme("partial-terminal")
+	require.NoError(t, err)
+	assert.Equal(t, "#C80000", got.Terminal.Red)
+	assert.NotContains(t, got.Synthesized, "terminal.red")
+	assert.Contains(t, got.Synthesized, "terminal.bright_red")
+	assert.Equal(t, "#FFFFFF", got.Terminal.Background)
+}
+
+func TestCompleteTerminalFromRawKeys(t *testing.T) {
+	// Themes imported before the palette existed only have raw keys.
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:     "legacy-vscode",
+		Type:   "dark",
+		Source: "vscode",
+		Colors: map[string]string{
+			"background":            "#101010",
+			"raw.terminal.ansiBlue": "#0000ee",
+		},
+	}))
+
+	got, err := svc.GetTheme("legacy-vscode")
+	require.NoError(t, err)
+	assert.Equal(t, "#0000EE", got.Terminal.Blue)
+	assert.Contains(t, got.Synthesized, "terminal.blue")
+}
+
+func TestTerminalPaletteInheritance(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:       "child-terminal",
+		Name:     "Child",
+		Extends:  "orchestra-dark",
+		Terminal: &types.TerminalPalette{Red: "#FF0000"},
+	}))
+
+	got, err := svc.GetTheme("child-terminal")
+	require.NoError(t, err)
+	assert.Equal(t, "#FF0000", got.Terminal.Red)
+	assert.Equal(t, builtin.DarkTheme().Terminal.Green, got.Terminal.Green)
+	assert.Empty(t, got.Synthesized)
+	assert.Equal(t, "#F38BA8", builtin.DarkTheme().Terminal.Red, "base palette is untouched")
+}
+
+func TestPatchTerminalPalette(t *testing.T) {
+	svc := newTestService(t)
+	registerCustom(t, svc, "patch-terminal")
+
+	patched, err := svc.PatchTheme("patch-terminal", []byte(`{"terminal": {"yellow": "#EEDD00"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "#EEDD00", patched.Terminal.Yellow)
+}
+
+func TestValidateTerminalColors(t *testing.T) {
+	d := validator.Validate(&types.ThemeDef{
+		ID:       "bad-terminal",
+		Name:     "Bad",
+		Type:     "dark",
+		Terminal: &types.TerminalPalette{Magenta: "not-a-color"},
+	})
+	require.True(t, d.HasErrors())
+	assert.Equal(t, "$.terminal.magenta", d.Errors()[0].Path)
+	assert.Equal(t, validator.CodeInvalidColor, d.Errors()[0].Code)
+}
+
+func TestExportVSCodeTerminalColors(t *testing.T) {
+	data, err := exporter.ExportVSCode(builtin.DarkTheme())
+	require.NoError(t, err)
+
+	var file struct {
+		Colors map[string]string `json:"colors"`
+	}
+	require.NoError(t, json.Unmarshal(data, &file))
+	assert.Equal(t, "#F38BA8", file.Colors["terminal.ansiRed"])
+	assert.Equal(t, "#F5E0DC", file.Colors["terminalCursor.foreground"])
+
+	back, err := importer.ImportVSCode(data)
+	require.NoError(t, err)
+	assert.Equal(t, builtin.DarkTheme().Terminal, back.Terminal)
+}
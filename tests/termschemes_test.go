@@ -0,0 +1,386 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/themes/src/builtin"
+	"github.com/orchestra-mcp/themes/src/exporter"
+	"github.com/orchestra-mcp/themes/src/importer"
+	"github.com/orchestra-mcp/themes/src/service"
+	"github.com/orchestra-mcp/themes/src/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var sampleAlacrittyTOML = []byte(`# Tomorrow Night
+[general]
+import = [
+  "~/.config/alacritty/base.toml", # shared
+]
+
+[colors.primary]
+background = '#1d1f21'
+foreground = "0xc5c8c6"
+
+[colors.cursor]
+text = "CellBackground"
+cursor = "CellForeground"
+
+[colors]
+selection = { text = "CellForeground", background = "#373b41" }
+
+[colors.normal]
+black   = '#1d1f21'
+red     = '#cc6666'
+green   = '#b5bd68'
+yellow  = '#f0c674'
+blue    = '#81a2be'
+magenta = '#b294bb'
+cyan    = '#8abeb7'
+white   = '#c5c8c6'
+
+[colors.bright]
+black = "#666666"
+red = "#d54e53"
+
+[[colors.indexed_colors]]
+index = 16
+color = "#ff0000"
+`)
+
+var sampleAlacrittyYAML = []byte(`# Colors (Gruvbox light)
+colors:
+  # Default colors
+  primary:
+    background: '0xfbf1c7'
+    foreground: "#3c3836"
+  normal:
+    black:   '0xfbf1c7'
+    red:     '0xcc241d'
+    green:   '0x98971a'
+  bright: { black: '0x928374', red: '0x9d0006' }
+  indexed_colors:
+    - { index: 16, color: '0xff0000' }
+    - index: 17
+      color: '0x00ff00'
+font:
+  size: 11
+`)
+
+var sampleKitty = []byte(`# vim:ft=kitty
+
+## name: Tokyo Night
+## author: Folke
+
+background #1a1b26
+foreground #c0caf5
+selection_background #283457
+cursor #c0caf5
+url_color #73daca
+
+# normal
+color0 #15161e
+color1 rgb:f7/76/8e
+color2 #9ece6a
+color15 #c0caf5
+`)
+
+var sampleWindowsTerminal = []byte(`{
+	"name": "Campbell",
+	"background": "#0C0C0C",
+	"foreground": "#CCCCCC",
+	"cursorColor": "#FFFFFF",
+	"selectionBackground": "#FFFFFF",
+	"black": "#0C0C0C",
+	"red": "#C50F1F",
+	"green": "#13A10E",
+	"yellow": "#C19C00",
+	"blue": "#0037DA",
+	"purple": "#881798",
+	"cyan": "#3A96DD",
+	"white": "#CCCCCC",
+	"brightBlack": "#767676",
+	"brightPurple": "#B4009E"
+}`)
+
+var sampleWindowsTerminalSettings = []byte(`{
+	// settings.json
+	"$schema": "https://aka.ms/terminal-profiles-schema",
+	"defaultProfile": "{61c54bbd-c2c6-5271-96e7-009a87ff44bf}",
+	"schemes": [
+		{"name": "One Half Light", "background": "#FAFAFA", "foreground": "#383A42", "black": "#383A42", "purple": "#A626A4"},
+		{"name": "One Half Dark", "background": "#282C34", "foreground": "#DCDFE4", "black": "#282C34", "purple": "#C678DD"},
+	],
+}`)
+
+var sampleXresources = []byte(`! Solarized
+#define S_base03        #002b36
+#define S_base0         #839496
+#define S_red           #dc322f
+#include "fonts"
+
+*background:            S_base03
+*foreground:            S_base0
+*.cursorColor:          #93a1a1
+URxvt*color0:           #073642
+*color1:                S_red
+XTerm.vt100.color9:     rgb:cb/4b/16
+*highlightColor:        #586e75
+URxvt.font:             xft:Hack:size=11
+`)
+
+func TestDetectTerminalSchemeFormats(t *testing.T) {
+	cases := map[string][]byte{
+		importer.FormatAlacritty:       sampleAlacrittyTOML,
+		importer.FormatKitty:           sampleKitty,
+		importer.FormatWindowsTerminal: sampleWindowsTerminal,
+		importer.FormatXresources:      sampleXresources,
+	}
+	for want, data := range cases {
+		assert.Equal(t, want, importer.DetectFormat(data), want)
+	}
+	assert.Equal(t, importer.FormatAlacritty, importer.DetectFormat(sampleAlacrittyYAML))
+	assert.Equal(t, importer.FormatWindowsTerminal, importer.DetectFormat(sampleWindowsTerminalSettings))
+	assert.Equal(t, importer.FormatOrchestraJSON, importer.DetectFormat([]byte("just some text")))
+}
+
+func TestImportAlacrittyTOML(t *testing.T) {
+	theme, err := importer.Import(sampleAlacrittyTOML)
+	require.NoError(t, err)
+
+	assert.Equal(t, "alacritty", theme.Source)
+	assert.Equal(t, "dark", theme.Type)
+	assert.Equal(t, "#1D1F21", theme.Colors["background"])
+	assert.Equal(t, "#C5C8C6", theme.Colors["editor.foreground"], "0x colors are accepted")
+	assert.Equal(t, "#373B41", theme.Colors["editor.selection"], "inline tables are read")
+	assert.NotContains(t, theme.Colors, "editor.cursor", "CellForeground is resolved at run time")
+
+	p := theme.Terminal
+	require.NotNil(t, p)
+	assert.Equal(t, "#CC6666", p.Red)
+	assert.Equal(t, "#C5C8C6", p.White)
+	assert.Equal(t, "#666666", p.BrightBlack)
+	assert.Equal(t, "#D54E53", p.BrightRed)
+	assert.Empty(t, p.BrightGreen)
+	assert.Empty(t, p.Cursor)
+}
+
+func TestImportAlacrittyYAML(t *testing.T) {
+	theme, err := importer.ImportAlacritty(sampleAlacrittyYAML)
+	require.NoError(t, err)
+
+	assert.Equal(t, "light", theme.Type)
+	assert.Equal(t, "#FBF1C7", theme.Terminal.Background)
+	assert.Equal(t, "#3C3836", theme.Terminal.Foreground)
+	assert.Equal(t, "#CC241D", theme.Terminal.Red)
+	assert.Equal(t, "#928374", theme.Terminal.BrightBlack, "flow mappings are read")
+	assert.Equal(t, "#9D0006", theme.Terminal.BrightRed)
+}
+
+func TestImportAlacrittyLargeUnterminatedArray(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("[colors.primary]\nbackground = '#1d1f21'\n[colors.normal]\nred = '#cc6666'\n\n[general]\nimport = [\n")
+	for b.Len() < 200<<10 {
+		b.WriteString("0,\n")
+	}
+
+	start := time.Now()
+	theme, err := importer.ImportAlacritty([]byte(b.String()))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, "#1D1F21", theme.Colors["background"])
+}
+
+func TestImportAlacrittySyntaxError(t *testing.T) {
+	_, err := importer.ImportAlacritty([]byte("[colors.primary]\nbackground '#000000'\n"))
+	var syntaxErr *importer.SyntaxError
+	require.True(t, errors.As(err, &syntaxErr), "got %v", err)
+	assert.Equal(t, 2, syntaxErr.Line)
+}
+
+func TestImportKitty(t *testing.T) {
+	theme, err := importer.Import(sampleKitty)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Tokyo Night", theme.Name, "named from the metadata comment")
+	assert.Equal(t, "tokyo-night", theme.ID)
+	assert.Equal(t, "kitty", theme.Source)
+	assert.Equal(t, "#1A1B26", theme.Colors["editor.background"])
+	assert.Equal(t, "#C0CAF5", theme.Colors["editor.cursor"])
+	assert.Equal(t, "#283457", theme.Terminal.Selection)
+	assert.Equal(t, "#15161E", theme.Terminal.Black)
+	assert.Equal(t, "#F7768E", theme.Terminal.Red, "X11 rgb: colors are accepted")
+	assert.Equal(t, "#C0CAF5", theme.Terminal.BrightWhite)
+}
+
+func TestImportWindowsTerminal(t *testing.T) {
+	theme, err := importer.Import(sampleWindowsTerminal)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Campbell", theme.Name)
+	assert.Equal(t, "campbell", theme.ID)
+	assert.Equal(t, "windows-terminal", theme.Source)
+	assert.Equal(t, "#881798", theme.Terminal.Magenta, "purple is magenta")
+	assert.Equal(t, "#B4009E", theme.Terminal.BrightMagenta)
+	assert.Equal(t, "#FFFFFF", theme.Terminal.Cursor)
+	assert.Equal(t, "#0C0C0C", theme.Colors["background"])
+
+	themes, err := importer.ImportWindowsTerminalSchemes(sampleWindowsTerminalSettings)
+	require.NoError(t, err)
+	require.Len(t, themes, 2)
+	assert.Equal(t, "one-half-light", themes[0].ID)
+	assert.Equal(t, "light", themes[0].Type)
+	assert.Equal(t, "dark", themes[1].Type)
+
+	_, err = importer.ImportWindowsTerminal(sampleWindowsTerminalSettings)
+	assert.ErrorIs(t, err, importer.ErrMultipleSchemes)
+
+	single, err := importer.ImportWindowsTerminal([]byte(`{"schemes": [{"name": "Solo", "background": "#000000", "purple": "#881798"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "solo", single.ID)
+
+	_, err = importer.ImportWindowsTerminal([]byte(`{"schemes": []}`))
+	assert.ErrorContains(t, err, "no color schemes")
+}
+
+func TestServiceImportWindowsTerminalSchemes(t *testing.T) {
+	svc := newTestService(t)
+	registerCustom(t, svc, "one-half-dark")
+
+	result, err := svc.ImportWindowsTerminalSchemes("settings.json", sampleWindowsTerminalSettings, service.DiscoverOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "settings.json", result.Extension)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 1, result.Skipped)
+	require.Len(t, result.Themes, 2)
+	assert.Equal(t, "schemes[0]", result.Themes[0].Path)
+	assert.Equal(t, "One Half Light", result.Themes[0].Label)
+	assert.NotEmpty(t, result.Themes[1].Skipped)
+
+	light, err := svc.GetTheme("one-half-light")
+	require.NoError(t, err)
+	assert.Equal(t, "#A626A4", light.Terminal.Magenta)
+
+	result, err = svc.ImportWindowsTerminalSchemes("settings.json", sampleWindowsTerminalSettings, service.DiscoverOptions{Overwrite: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+	dark, err := svc.GetRawTheme("one-half-dark")
+	require.NoError(t, err)
+	assert.Equal(t, "windows-terminal", dark.Source)
+}
+
+func TestImportXresources(t *testing.T) {
+	theme, err := importer.Import(sampleXresources)
+	require.NoError(t, err)
+
+	assert.Equal(t, "xresources", theme.Source)
+	assert.Equal(t, "#002B36", theme.Terminal.Background, "macros are expanded")
+	assert.Equal(t, "#839496", theme.Colors["foreground"])
+	assert.Equal(t, "#93A1A1", theme.Terminal.Cursor)
+	assert.Equal(t, "#073642", theme.Terminal.Black)
+	assert.Equal(t, "#DC322F", theme.Terminal.Red)
+	assert.Equal(t, "#CB4B16", theme.Terminal.BrightRed)
+	assert.Equal(t, "#586E75", theme.Terminal.Selection)
+}
+
+func TestImportTerminalSchemeErrors(t *testing.T) {
+	_, err := importer.ImportKitty([]byte("background #000000\nforeground #ffffff\n"))
+	assert.ErrorContains(t, err, "no ANSI colors")
+
+	_, err = importer.ImportXresources([]byte("*color1: notacolor\n"))
+	assert.ErrorContains(t, err, `invalid red color "notacolor"`)
+}
+
+func TestImportFileNamesTerminalSchemes(t *testing.T) {
+	theme, err := importer.ImportFile("/home/me/.config/alacritty/Tomorrow Night.toml", sampleAlacrittyTOML)
+	require.NoError(t, err)
+	assert.Equal(t, "Tomorrow Night", theme.Name)
+	assert.Equal(t, "tomorrow-night", theme.ID)
+
+	kitty, err := importer.ImportFile("tokyo.conf", sampleKitty)
+	require.NoError(t, err)
+	assert.Equal(t, "Tokyo Night", kitty.Name, "embedded names are kept")
+}
+
+func TestExportTerminalSchemesRoundTrip(t *testing.T) {
+	dark := builtin.DarkTheme()
+	for _, format := range exporter.TerminalFormats() {
+		data, err := exporter.Export(dark, format)
+		require.NoError(t, err, format)
+		assert.Equal(t, format, importer.DetectFormat(data), format)
+
+		back, err := importer.Import(data)
+		require.NoError(t, err, format)
+		assert.Equal(t, dark.Terminal, back.Terminal, format)
+		assert.Equal(t, "dark", back.Type, format)
+	}
+}
+
+func TestExportTerminalSchemeContents(t *testing.T) {
+	theme := &types.ThemeDef{
+		Name:   "Half\nLit",
+		Author: "Someone",
+		Colors: map[string]string{"editor.background": "#000000", "editor.foreground": "#EEEEEE"},
+		Terminal: &types.TerminalPalette{
+			Red:       "#FF0000",
+			Magenta:   "#AA00AA",
+			Selection: "#FFFFFF80",
+		},
+	}
+
+	kitty, err := exporter.ExportKitty(theme)
+	require.NoError(t, err)
+	assert.Contains(t, string(kitty), "## name: Half Lit\n## author: Someone\n")
+	assert.Contains(t, string(kitty), "background #000000\n")
+	assert.Contains(t, string(kitty), "selection_background #808080\n", "alpha is blended over the background")
+	assert.Contains(t, string(kitty), "color1 #FF0000\n")
+	assert.NotContains(t, string(kitty), "color2 ", "unset colors are omitted")
+
+	toml, err := exporter.ExportAlacritty(theme)
+	require.NoError(t, err)
+	assert.Contains(t, string(toml), "[colors.normal]\nred = \"#FF0000\"\nmagenta = \"#AA00AA\"\n")
+	assert.NotContains(t, string(toml), "[colors.bright]")
+
+	wt, err := exporter.ExportWindowsTerminal(theme)
+	require.NoError(t, err)
+	assert.Contains(t, string(wt), `"purple": "#AA00AA"`)
+
+	xres, err := exporter.ExportXresources(theme)
+	require.NoError(t, err)
+	assert.Contains(t, string(xres), "*.color5: #AA00AA\n")
+	assert.Contains(t, string(xres), "*.foreground: #EEEEEE\n")
+}
+
+func TestExportITermColorsRestoresRawColors(t *testing.T) {
+	theme, err := importer.ImportITermColors(sampleITermColors)
+	require.NoError(t, err)
+
+	data, err := exporter.ExportITermColors(theme)
+	require.NoError(t, err)
+	back, err := importer.ImportITermColors(data)
+	require.NoError(t, err)
+	assert.Equal(t, "#0000FF80", back.Colors["raw.Link Color"])
+	assert.Equal(t, theme.Terminal, back.Terminal)
+	assert.Equal(t, "application/xml", exporter.ContentType(exporter.FormatITermColors))
+}
+
+func TestServiceExportsDerivedTerminalPalette(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:     "no-palette",
+		Name:   "No Palette",
+		Type:   "dark",
+		Colors: map[string]string{"background": "#101010", "foreground": "#F0F0F0", "error": "#FF4444"},
+	}))
+
+	data, err := svc.ExportTheme("no-palette", service.ExportOptions{Raw: true, Format: exporter.FormatKitty})
+	require.NoError(t, err)
+	back, err := importer.ImportKitty(data)
+	require.NoError(t, err)
+	assert.Equal(t, "No Palette", back.Name)
+	assert.Equal(t, "#FF4444", back.Terminal.Red)
+	assert.NotEmpty(t, back.Terminal.BrightCyan, "the completed palette is exported")
+}
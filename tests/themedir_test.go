@@ -0,0 +1,246 @@
+package tests
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/themes/config"
+	"github.com/orchestra-mcp/themes/providers"
+	"github.com/orchestra-mcp/themes/src/service"
+	"github.com/orchestra-mcp/themes/src/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeThemeFile writes a theme file and moves its modification time
+// forward so that every write is seen as a change.
+func writeThemeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	var next time.Time
+	if info, err := os.Stat(path); err == nil {
+		next = info.ModTime().Add(time.Second)
+	} else {
+		next = time.Now()
+	}
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	require.NoError(t, os.Chtimes(path, next, next))
+}
+
+func orchestraThemeJSON(id, bg string) string {
+	return `{"id": "` + id + `", "name": "` + id + `", "type": "dark",
+		"colors": {"background": "` + bg + `", "foreground": "#FFFFFF"}}`
+}
+
+func TestWatchThemeDirLoadsFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeThemeFile(t, dir, "ocean.json", orchestraThemeJSON("ocean", "#001122"))
+	writeThemeFile(t, dir, "one-dark.json", string(sampleVSCodeTheme))
+	writeThemeFile(t, dir, "notes.txt", "not a theme")
+	writeThemeFile(t, dir, ".hidden.json", orchestraThemeJSON("hidden", "#000000"))
+
+	svc := newTestService(t)
+	w := svc.WatchThemeDir(dir, time.Hour)
+	defer w.Stop()
+
+	ocean, err := svc.GetTheme("ocean")
+	require.NoError(t, err)
+	assert.Equal(t, "#001122", ocean.Colors["background"])
+	_, err = svc.GetTheme("one-dark-pro")
+	assert.NoError(t, err)
+	_, err = svc.GetTheme("hidden")
+	assert.ErrorIs(t, err, service.ErrThemeNotFound)
+}
+
+func TestWatchThemeDirPicksUpChanges(t *testing.T) {
+	dir := t.TempDir()
+	svc := newTestService(t)
+	w := svc.WatchThemeDir(dir, time.Hour)
+	defer w.Stop()
+
+	writeThemeFile(t, dir, "ocean.json", orchestraThemeJSON("ocean", "#001122"))
+	w.Sync()
+	require.NoError(t, svc.SetActiveTheme("ocean"))
+
+	var events [][2]*types.ThemeDef
+	svc.OnDidChangeTheme(func(old, new *types.ThemeDef) {
+		events = append(events, [2]*types.ThemeDef{old, new})
+	})
+
+	writeThemeFile(t, dir, "ocean.json", orchestraThemeJSON("ocean", "#002244"))
+	w.Sync()
+	require.Len(t, events, 1, "editing the active theme's file notifies listeners")
+	assert.Equal(t, "#001122", events[0][0].Colors["background"])
+	assert.Equal(t, "#002244", events[0][1].Colors["background"])
+
+	w.Sync()
+	assert.Len(t, events, 1, "unchanged files are not reloaded")
+
+	writeThemeFile(t, dir, "forest.json", orchestraThemeJSON("forest", "#002200"))
+	w.Sync()
+	assert.Len(t, events, 1, "other themes do not affect the active theme")
+	_, err := svc.GetTheme("forest")
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "ocean.json")))
+	w.Sync()
+	_, err = svc.GetTheme("ocean")
+	assert.ErrorIs(t, err, service.ErrThemeNotFound)
+	require.Len(t, events, 2)
+	assert.Equal(t, "orchestra-dark", svc.GetActiveTheme().ID, "falls back to the default theme")
+}
+
+func TestWatchThemeDirKeepsThemeOnBrokenEdit(t *testing.T) {
+	dir := t.TempDir()
+	writeThemeFile(t, dir, "ocean.json", orchestraThemeJSON("ocean", "#001122"))
+	svc := newTestService(t)
+	w := svc.WatchThemeDir(dir, time.Hour)
+	defer w.Stop()
+
+	writeThemeFile(t, dir, "ocean.json", `{"id": "ocean", `)
+	w.Sync()
+	theme, err := svc.GetTheme("ocean")
+	require.NoError(t, err)
+	assert.Equal(t, "#001122", theme.Colors["background"])
+}
+
+func TestWatchThemeDirResolvesExtendsAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeThemeFile(t, dir, "a-child.json", `{"id": "child", "name": "Child", "extends": "zz-base",
+		"colors": {"background": "#111111"}}`)
+	writeThemeFile(t, dir, "b-base.json", orchestraThemeJSON("zz-base", "#222222"))
+
+	svc := newTestService(t)
+	w := svc.WatchThemeDir(dir, time.Hour)
+	defer w.Stop()
+
+	child, err := svc.GetTheme("child")
+	require.NoError(t, err)
+	assert.Equal(t, "#FFFFFF", child.Colors["foreground"])
+}
+
+func TestWatchThemeDirRetriesFailedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeThemeFile(t, dir, "child.json", `{"id": "child", "name": "Child", "extends": "base",
+		"colors": {"background": "#111111"}}`)
+
+	svc := newTestService(t)
+	w := svc.WatchThemeDir(dir, time.Hour)
+	defer w.Stop()
+	_, err := svc.GetTheme("child")
+	require.ErrorIs(t, err, service.ErrThemeNotFound)
+
+	writeThemeFile(t, dir, "base.json", orchestraThemeJSON("base", "#222222"))
+	w.Sync()
+	child, err := svc.GetTheme("child")
+	require.NoError(t, err, "the unchanged child file is retried once its base exists")
+	assert.Equal(t, "#111111", child.Colors["background"])
+	assert.Equal(t, "#FFFFFF", child.Colors["foreground"])
+}
+
+func TestWatchThemeDirLoadsFileAfterClashIsRemoved(t *testing.T) {
+	dir := t.TempDir()
+	svc := newTestService(t)
+	registerCustom(t, svc, "mine")
+	writeThemeFile(t, dir, "mine.json", orchestraThemeJSON("mine", "#123456"))
+
+	w := svc.WatchThemeDir(dir, time.Hour)
+	defer w.Stop()
+	require.NoError(t, svc.DeleteTheme("mine"))
+
+	w.Sync()
+	mine, err := svc.GetTheme("mine")
+	require.NoError(t, err)
+	assert.Equal(t, "#123456", mine.Colors["background"])
+}
+
+func TestDirThemesAreReadOnlyAndNotStored(t *testing.T) {
+	dir := t.TempDir()
+	storage := t.TempDir()
+	writeThemeFile(t, dir, "ocean.json", orchestraThemeJSON("ocean", "#001122"))
+
+	svc := service.New(storage, "orchestra-dark", zerolog.Nop())
+	w := svc.WatchThemeDir(dir, time.Hour)
+	defer w.Stop()
+
+	assert.ErrorIs(t, svc.DeleteTheme("ocean"), service.ErrDirTheme)
+	_, err := svc.UpdateTheme("ocean", &types.ThemeDef{Name: "Ocean", Type: "dark"})
+	assert.ErrorIs(t, err, service.ErrDirTheme)
+	err = svc.RegisterTheme(&types.ThemeDef{ID: "ocean", Name: "Ocean", Type: "dark"})
+	assert.ErrorIs(t, err, service.ErrDirTheme)
+
+	registerCustom(t, svc, "mine")
+	require.NoError(t, svc.SetActiveTheme("ocean"))
+
+	// A restarted service keeps user themes but not directory themes, and
+	// activates the preferred directory theme once its file loads.
+	restarted := service.New(storage, "orchestra-dark", zerolog.Nop())
+	_, err = restarted.GetTheme("mine")
+	require.NoError(t, err)
+	_, err = restarted.GetTheme("ocean")
+	require.ErrorIs(t, err, service.ErrThemeNotFound)
+	assert.Equal(t, "orchestra-dark", restarted.GetActiveTheme().ID)
+
+	w2 := restarted.WatchThemeDir(dir, time.Hour)
+	defer w2.Stop()
+	assert.Equal(t, "ocean", restarted.GetActiveTheme().ID)
+}
+
+func TestWatchThemeDirSkipsConflictingIDs(t *testing.T) {
+	dir := t.TempDir()
+	svc := newTestService(t)
+	registerCustom(t, svc, "mine")
+	writeThemeFile(t, dir, "mine.json", orchestraThemeJSON("mine", "#123456"))
+	writeThemeFile(t, dir, "builtin.json", orchestraThemeJSON("orchestra-dark", "#123456"))
+
+	w := svc.WatchThemeDir(dir, time.Hour)
+	defer w.Stop()
+
+	mine, err := svc.GetTheme("mine")
+	require.NoError(t, err)
+	assert.Equal(t, "#000000", mine.Colors["background"])
+	assert.NoError(t, svc.DeleteTheme("mine"), "user theme stays writable")
+}
+
+func TestWatchThemeDirPolls(t *testing.T) {
+	dir := t.TempDir()
+	svc := newTestService(t)
+	w := svc.WatchThemeDir(dir, 10*time.Millisecond)
+
+	writeThemeFile(t, dir, "ocean.json", orchestraThemeJSON("ocean", "#001122"))
+	require.Eventually(t, func() bool {
+		_, err := svc.GetTheme("ocean")
+		return err == nil
+	}, 2*time.Second, 10*time.Millisecond)
+
+	w.Stop()
+	w.Stop()
+	writeThemeFile(t, dir, "forest.json", orchestraThemeJSON("forest", "#002200"))
+	time.Sleep(50 * time.Millisecond)
+	_, err := svc.GetTheme("forest")
+	assert.ErrorIs(t, err, service.ErrThemeNotFound, "a stopped watcher no longer scans")
+}
+
+func TestDefaultConfigDecodesPollInterval(t *testing.T) {
+	data, err := json.Marshal(providers.NewThemesPlugin().DefaultConfig())
+	require.NoError(t, err)
+
+	var cfg config.ThemesConfig
+	require.NoError(t, json.Unmarshal(data, &cfg))
+	interval, err := cfg.PollInterval()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, interval)
+
+	interval, err = config.DefaultConfig().PollInterval()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, interval)
+
+	for _, bad := range []string{"", "soon", "0s", "-1s"} {
+		_, err = (&config.ThemesConfig{ThemesPollInterval: bad}).PollInterval()
+		assert.Error(t, err, bad)
+	}
+}
@@ -1,7 +1,9 @@
 package tests
 
 import (
+	"encoding/base64"
 	"testing"
+	"time"
 
 	"github.com/orchestra-mcp/themes/src/importer"
 	"github.com/stretchr/testify/assert"
@@ -89,11 +91,13 @@ func TestImportTmThemeGlobalColors(t *testing.T) {
 	theme, err := importer.ImportTmTheme(sampleTmTheme)
 	require.NoError(t, err)
 
-	assert.Equal(t, "#272822", theme.Colors["bg-primary"])
-	assert.Equal(t, "#F8F8F2", theme.Colors["text-primary"])
-	assert.Equal(t, "#F8F8F0", theme.Colors["caret"])
-	assert.Equal(t, "#49483E", theme.Colors["bg-selection"])
-	assert.Equal(t, "#3E3D32", theme.Colors["bg-line-highlight"])
+	assert.Equal(t, "#272822", theme.Colors["editor.background"])
+	assert.Equal(t, "#272822", theme.Colors["background"])
+	assert.Equal(t, "#F8F8F2", theme.Colors["editor.foreground"])
+	assert.Equal(t, "#F8F8F2", theme.Colors["foreground"])
+	assert.Equal(t, "#F8F8F0", theme.Colors["editor.cursor"])
+	assert.Equal(t, "#49483E", theme.Colors["editor.selection"])
+	assert.Equal(t, "#3E3D32", theme.Colors["editor.lineHighlight"])
 	assert.Equal(t, "#272822", theme.Colors["raw.background"])
 	assert.Equal(t, "#F8F8F2", theme.Colors["raw.foreground"])
 }
@@ -164,6 +168,118 @@ func TestImportTmThemeLightDetection(t *testing.T) {
 	assert.Equal(t, "light", theme.Type)
 }
 
+// --- Property Lists ---
+
+// sampleBinaryTmTheme is a binary plist tmTheme that also carries
+// integer, real, boolean, data, date and string array values. It was
+// written by Python's plistlib.
+var sampleBinaryTmTheme, _ = base64.StdEncoding.DecodeString("" +
+	"YnBsaXN0MDDcAQIDBAUGBwgJCgsMDQ4PEBESExQVFhcuVG5hbWVWYXV0aG9yXXNlbWFudGljQ2xh" +
+	"c3NUdXVpZFd2ZXJzaW9uVXJhdGlvVmhpZGRlblRiZXRhVGljb25YbW9kaWZpZWRUdGFnc1hzZXR0" +
+	"aW5nc28QEABCAGkAbgDkAHIAeQAgAE0AbwBuAG8AawBhAGkAICcTVFRlc3RfEBF0aGVtZS5kYXJr" +
+	"LmJpbmFyeV8QJEQ4RDVFODJFLTNENUItNDZCNS1CMzhFLThDODQxQzIxMzQ3RBADIz/4AAAAAAAA" +
+	"CAlMAAECb3JjaGVzdHJhM0HHoFEkAAAArxAWGBkaGxwdHh8gISIjJCUmJygpKissLVRkYXJrV21v" +
+	"bm9rYWlSdDBSdDFSdDJSdDNSdDRSdDVSdDZSdDdSdDhSdDlTdDEwU3QxMVN0MTJTdDEzU3QxNFN0" +
+	"MTVTdDE2U3QxN1N0MThTdDE5oy83PdEMMNMxMjM0NTZaYmFja2dyb3VuZFpmb3JlZ3JvdW5kVWNh" +
+	"cmV0VyMyNzI4MjJXI0Y4RjhGMlcjRjhGOEYw0wE4DDk6O1VzY29wZVdDb21tZW50V2NvbW1lbnTR" +
+	"MjxXIzc1NzE1RdMBOAw+P0BXS2V5d29yZF8QEGtleXdvcmQsIHN0b3JhZ2XSMkFCQ1lmb250U3R5" +
+	"bGVXI0Y5MjY3MlRib2xkAAgAIQAmAC0AOwBAAEgATgBVAFoAXwBoAG0AdgCZAJ4AsgDZANsA5ADl" +
+	"AOYA8wD8ARUBGgEiASUBKAErAS4BMQE0ATcBOgE9AUABRAFIAUwBUAFUAVgBXAFgAWQBaAFsAW8B" +
+	"dgGBAYwBkgGaAaIBqgGxAbcBvwHHAcoB0gHZAeEB9AH5AgMCCwAAAAAAAAIBAAAAAAAAAEQAAAAA" +
+	"AAAAAAAAAAAAAAIQ")
+
+func TestDecodePlistXMLTypes(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>name</key><string>Types</string>
+	<key>version</key><integer>-3</integer>
+	<key>ratio</key><real>0.25</real>
+	<key>beta</key><true/>
+	<key>hidden</key><false/>
+	<key>icon</key><data>
+		AAEC
+	</data>
+	<key>modified</key><date>2026-02-14T12:30:00Z</date>
+	<key>tags</key><array><string>dark</string><integer>1</integer></array>
+	<key>empty</key><dict/>
+</dict>
+</plist>`)
+
+	v, err := importer.DecodePlist(data)
+	require.NoError(t, err)
+	root, ok := v.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "Types", root["name"])
+	assert.Equal(t, int64(-3), root["version"])
+	assert.Equal(t, 0.25, root["ratio"])
+	assert.Equal(t, true, root["beta"])
+	assert.Equal(t, false, root["hidden"])
+	assert.Equal(t, []byte{0, 1, 2}, root["icon"])
+	assert.Equal(t, time.Date(2026, 2, 14, 12, 30, 0, 0, time.UTC), root["modified"])
+	assert.Equal(t, []any{"dark", int64(1)}, root["tags"])
+	assert.Equal(t, map[string]any{}, root["empty"])
+}
+
+func TestDecodePlistXMLErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"bad integer":   `<plist><dict><key>n</key><integer>x</integer></dict></plist>`,
+		"value w/o key": `<plist><dict><string>orphan</string></dict></plist>`,
+		"unknown":       `<plist><dict><key>n</key><color>red</color></dict></plist>`,
+		"empty":         `<plist></plist>`,
+	} {
+		_, err := importer.DecodePlist([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestDecodeBinaryPlist(t *testing.T) {
+	v, err := importer.DecodePlist(sampleBinaryTmTheme)
+	require.NoError(t, err)
+	root, ok := v.(map[string]any)
+	require.True(t, ok)
+
+	assert.Equal(t, "Binäry Monokai ✓", root["name"], "UTF-16 strings")
+	assert.Equal(t, "theme.dark.binary", root["semanticClass"])
+	assert.Equal(t, int64(3), root["version"])
+	assert.Equal(t, 1.5, root["ratio"])
+	assert.Equal(t, true, root["beta"])
+	assert.Equal(t, false, root["hidden"])
+	assert.Equal(t, []byte("\x00\x01\x02orchestra"), root["icon"])
+	assert.True(t, time.Date(2026, 2, 14, 12, 30, 0, 0, time.UTC).Equal(root["modified"].(time.Time)))
+
+	tags, ok := root["tags"].([]any)
+	require.True(t, ok)
+	assert.Len(t, tags, 22, "lengths above 14 use an extended count")
+	assert.Equal(t, "t19", tags[21])
+}
+
+func TestDecodeBinaryPlistTruncated(t *testing.T) {
+	for _, n := range []int{8, 40, len(sampleBinaryTmTheme) - 1} {
+		_, err := importer.DecodePlist(sampleBinaryTmTheme[:n])
+		assert.Error(t, err, "length %d", n)
+	}
+}
+
+func TestImportBinaryTmTheme(t *testing.T) {
+	assert.Equal(t, importer.FormatTmTheme, importer.DetectFormat(sampleBinaryTmTheme))
+
+	theme, err := importer.Import(sampleBinaryTmTheme)
+	require.NoError(t, err)
+	assert.Equal(t, "Binäry Monokai ✓", theme.Name)
+	assert.Equal(t, "binry-monokai", theme.ID)
+	assert.Equal(t, "#272822", theme.Colors["editor.background"])
+	require.Len(t, theme.TokenColors, 2)
+	assert.Equal(t, []string{"keyword", "storage"}, theme.TokenColors[1].Scope)
+	assert.Equal(t, "bold", theme.TokenColors[1].Settings["fontStyle"])
+}
+
+func TestImportTmThemeInvalidBinary(t *testing.T) {
+	_, err := importer.ImportTmTheme([]byte("bplist00 broken"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid tmTheme binary plist")
+}
+
 // --- Unified Import ---
 
 func TestImportAutoDetectsTmTheme(t *testing.T) {
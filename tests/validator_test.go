@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/orchestra-mcp/themes/src/builtin"
+	"github.com/orchestra-mcp/themes/src/service"
+	"github.com/orchestra-mcp/themes/src/types"
+	"github.com/orchestra-mcp/themes/src/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findDiag(diags validator.Diagnostics, path string) *validator.Diagnostic {
+	for i := range diags {
+		if diags[i].Path == path {
+			return &diags[i]
+		}
+	}
+	return nil
+}
+
+func TestValidateBuiltinsClean(t *testing.T) {
+	for _, theme := range builtin.BuiltinThemes() {
+		assert.Empty(t, validator.Validate(theme), theme.ID)
+	}
+}
+
+func TestValidateReportsPathsAndSeverities(t *testing.T) {
+	theme := &types.ThemeDef{
+		ID:   "Bad ID",
+		Type: "sepia",
+		Colors: map[string]string{
+			"background":        "#12345",
+			"bg-primary":        "#000000",
+			"made.up":           "#FFFFFF",
+			"raw.tab.something": "not a color",
+		},
+		TokenColors: []types.TokenColor{
+			{Scope: nil, Settings: map[string]string{"foreground": "#FFF"}},
+			{Scope: []string{"comment", " "}, Settings: map[string]string{
+				"foreground": "blurple",
+				"fontStyle":  "italic wavy",
+				"opacity":    "0.5",
+			}},
+		},
+	}
+	diags := validator.Validate(theme)
+	require.True(t, diags.HasErrors())
+
+	cases := []struct {
+		path, severity, code string
+	}{
+		{"$.id", validator.SeverityError, validator.CodeInvalidID},
+		{"$.name", validator.SeverityWarning, validator.CodeMissingName},
+		{"$.type", validator.SeverityError, validator.CodeInvalidType},
+		{"$.colors['background']", validator.SeverityError, validator.CodeInvalidColor},
+		{"$.colors['bg-primary']", validator.SeverityInfo, validator.CodeAliasKey},
+		{"$.colors['made.up']", validator.SeverityWarning, validator.CodeUnknownKey},
+		{"$.token_colors[0].scope", validator.SeverityWarning, validator.CodeEmptyScope},
+		{"$.token_colors[1].scope[1]", validator.SeverityError, validator.CodeEmptyScope},
+		{"$.token_colors[1].settings.foreground", validator.SeverityError, validator.CodeInvalidColor},
+		{"$.token_colors[1].settings.fontStyle", validator.SeverityError, validator.CodeInvalidFontStyle},
+		{"$.token_colors[1].settings.opacity", validator.SeverityWarning, validator.CodeUnknownSetting},
+	}
+	for _, tc := range cases {
+		d := findDiag(diags, tc.path)
+		if assert.NotNil(t, d, "expected diagnostic at %s", tc.path) {
+			assert.Equal(t, tc.severity, d.Severity, tc.path)
+			assert.Equal(t, tc.code, d.Code, tc.path)
+		}
+	}
+	assert.Nil(t, findDiag(diags, "$.colors['raw.tab.something']"), "raw keys are not checked")
+}
+
+func TestValidateMissingID(t *testing.T) {
+	diags := validator.Validate(&types.ThemeDef{Name: "x", Type: "dark"})
+	d := findDiag(diags, "$.id")
+	require.NotNil(t, d)
+	assert.Equal(t, validator.CodeMissingID, d.Code)
+}
+
+func TestServiceValidateThemeChecksExtends(t *testing.T) {
+	svc := newTestService(t)
+	diags := svc.ValidateTheme(&types.ThemeDef{ID: "child", Name: "Child", Extends: "ghost"})
+	d := findDiag(diags, "$.extends")
+	require.NotNil(t, d)
+	assert.Equal(t, validator.CodeInvalidExtends, d.Code)
+	assert.True(t, diags.HasErrors())
+
+	diags = svc.ValidateTheme(&types.ThemeDef{ID: "child", Name: "Child", Extends: "orchestra-dark"})
+	assert.False(t, diags.HasErrors())
+}
+
+func TestImportRejectsInvalidTheme(t *testing.T) {
+	svc := newTestService(t)
+	_, err := svc.ImportTheme([]byte(`{"id":"ok-id","type":"purple","colors":{"background":"#000"}}`))
+	require.ErrorIs(t, err, service.ErrInvalidTheme)
+
+	var verr *validator.Error
+	require.True(t, errors.As(err, &verr))
+	assert.NotNil(t, findDiag(verr.Diagnostics, "$.type"))
+
+	_, err = svc.GetTheme("ok-id")
+	assert.ErrorIs(t, err, service.ErrThemeNotFound)
+}
+
+func TestImportAcceptsWarnings(t *testing.T) {
+	svc := newTestService(t)
+	_, err := svc.ImportTheme([]byte(`{"id":"warned","colors":{"custom.key":"#000"}}`))
+	assert.NoError(t, err)
+}
+
+func TestValidateSemanticTokenColors(t *testing.T) {
+	theme := &types.ThemeDef{
+		ID:   "semantic",
+		Name: "Semantic",
+		Type: "dark",
+		SemanticTokenColors: []types.SemanticTokenColor{
+			{Selector: "variable.readonly", Style: types.SemanticTokenStyle{Foreground: "#FFFFFF"}},
+			{Selector: "variable.", Style: types.SemanticTokenStyle{Foreground: "#FFFFFF"}},
+			{Selector: "class", Style: types.SemanticTokenStyle{Foreground: "blurple", FontStyle: "bold wavy"}},
+		},
+	}
+	diags := validator.Validate(theme)
+	assert.Nil(t, findDiag(diags, "$.semantic_token_colors[0].selector"))
+
+	sel := findDiag(diags, "$.semantic_token_colors[1].selector")
+	require.NotNil(t, sel)
+	assert.Equal(t, validator.CodeInvalidSelector, sel.Code)
+
+	fg := findDiag(diags, "$.semantic_token_colors[2].style.foreground")
+	require.NotNil(t, fg)
+	assert.Equal(t, validator.CodeInvalidColor, fg.Code)
+
+	fs := findDiag(diags, "$.semantic_token_colors[2].style.font_style")
+	require.NotNil(t, fs)
+	assert.Equal(t, validator.SeverityError, fs.Severity)
+}
+
+func TestRegisterNormalizesSemanticTokenColors(t *testing.T) {
+	svc := newTestService(t)
+	require.NoError(t, svc.RegisterTheme(&types.ThemeDef{
+		ID:   "semantic",
+		Name: "Semantic",
+		Type: "dark",
+		SemanticTokenColors: []types.SemanticTokenColor{
+			{Selector: "enumMember:rust", Style: types.SemanticTokenStyle{Foreground: "rgb(255, 0, 0)"}},
+		},
+	}))
+	raw, err := svc.GetRawTheme("semantic")
+	require.NoError(t, err)
+	rule := raw.SemanticTokenColors[0]
+	assert.Equal(t, "#FF0000", rule.Style.Foreground)
+	assert.Equal(t, "enumMember", rule.TokenType)
+	assert.Equal(t, "rust", rule.Language)
+}